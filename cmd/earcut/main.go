@@ -0,0 +1,294 @@
+// Command earcut triangulates polygons read from a file or stdin and prints the triangle
+// indices and the triangulation's deviation as JSON.
+//
+// Usage:
+//
+//	earcut [-format auto|json|geojson|wkt|wkb] [-svg out.svg [-labels]] [-obj out.obj] [-stl out.stl] [-ply out.ply] [-glb out.glb] [file]
+//
+// The json format is either Earcut's flat form {"vertices": [...], "holes": [...], "dimensions": 2}
+// or an array of rings [[[x, y], ...], ...] as accepted by Flatten. The wkb format is hex-encoded.
+// The output holds the vertices the indices refer to, which differ from the input when rings are
+// closed (GeoJSON, WKT) or several polygons are combined.
+//
+// The GLB file has one node per polygon, named after the "name" property or the id of the
+// GeoJSON feature it comes from, or after its index otherwise.
+//
+// The exit code is 0 on success, 1 when the input is invalid and 2 on usage errors.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"earcut-go/pkg/earcut"
+	"earcut-go/pkg/earcut/export"
+	"earcut-go/pkg/earcut/geojson"
+	"earcut-go/pkg/earcut/wkt"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// result is what gets printed on stdout
+type result struct {
+	Vertices   []float64 `json:"vertices"`
+	Dimensions int       `json:"dimensions"`
+	Triangles  []int     `json:"triangles"`
+	Deviation  deviation `json:"deviation"`
+	// the triangulation of each polygon on its own
+	meshes []export.Mesh
+}
+
+// deviation is encoded as null when infinite, e.g. for a figure-eight ring whose signed area is 0
+type deviation float64
+
+func (d deviation) MarshalJSON() ([]byte, error) {
+	if math.IsInf(float64(d), 0) || math.IsNaN(float64(d)) {
+		return []byte("null"), nil
+	}
+	return json.Marshal(float64(d))
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("earcut", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	format := flags.String("format", "auto", "input format: auto, json, geojson, wkt or wkb (hex)")
+	svgPath := flags.String("svg", "", "also write an SVG drawing of the triangulation to this file")
+	labels := flags.Bool("labels", false, "number the vertices in the SVG drawing")
+	objPath := flags.String("obj", "", "also write a Wavefront OBJ mesh to this file")
+	stlPath := flags.String("stl", "", "also write a binary STL mesh to this file")
+	plyPath := flags.String("ply", "", "also write a PLY mesh to this file")
+	glbPath := flags.String("glb", "", "also write a binary glTF mesh to this file")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: earcut [flags] [file]")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() > 1 {
+		flags.Usage()
+		return 2
+	}
+
+	input, err := readInput(flags.Arg(0), stdin)
+	if err != nil {
+		fmt.Fprintln(stderr, "earcut:", err)
+		return 1
+	}
+
+	kind := *format
+	if kind == "auto" {
+		kind = detectFormat(input)
+	}
+	polygons, names, err := parse(kind, input)
+	if errors.Is(err, errUnknownFormat) {
+		fmt.Fprintln(stderr, "earcut:", err)
+		return 2
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, "earcut:", err)
+		return 1
+	}
+
+	res, err := triangulate(polygons)
+	if err != nil {
+		fmt.Fprintln(stderr, "earcut:", err)
+		return 1
+	}
+
+	if *svgPath != "" {
+		draw := func(w io.Writer) error {
+			return export.WriteSVG(w, polygons, res.Triangles, export.SVGOptions{Labels: *labels, Bridges: true})
+		}
+		if err := writeFile(*svgPath, draw); err != nil {
+			fmt.Fprintln(stderr, "earcut:", err)
+			return 1
+		}
+	}
+	mesh := export.Mesh{Vertices: res.Vertices, Dim: res.Dimensions, Triangles: res.Triangles}
+	meshes := []struct {
+		path  string
+		write func(io.Writer, export.Mesh) error
+	}{
+		{*objPath, export.WriteOBJ},
+		{*stlPath, export.WriteBinarySTL},
+		{*plyPath, export.WritePLY},
+	}
+	for _, m := range meshes {
+		if m.path == "" {
+			continue
+		}
+		if err := writeFile(m.path, func(w io.Writer) error { return m.write(w, mesh) }); err != nil {
+			fmt.Fprintln(stderr, "earcut:", err)
+			return 1
+		}
+	}
+
+	if *glbPath != "" {
+		names := polygonNames(names, len(polygons))
+		if err := writeFile(*glbPath, func(w io.Writer) error { return export.WriteGLB(w, res.meshes, names) }); err != nil {
+			fmt.Fprintln(stderr, "earcut:", err)
+			return 1
+		}
+	}
+
+	if err := json.NewEncoder(stdout).Encode(res); err != nil {
+		fmt.Fprintln(stderr, "earcut:", err)
+		return 1
+	}
+	return 0
+}
+
+func readInput(path string, stdin io.Reader) ([]byte, error) {
+	if path == "" || path == "-" {
+		return io.ReadAll(stdin)
+	}
+	return os.ReadFile(path)
+}
+
+var errUnknownFormat = errors.New("unknown format")
+
+// parse input in the given format into polygons ready for Earcut, along with the names of the
+// GeoJSON features they come from
+func parse(format string, input []byte) (polygons []earcut.Polygon, names []string, err error) {
+	switch format {
+	case "json":
+		polygons, err = parseJSON(input)
+		return polygons, nil, err
+	case "geojson":
+		return geojson.DecodePolygons(bytes.NewReader(input))
+	case "wkt":
+		g, err := wkt.Parse(string(input))
+		if err != nil {
+			return nil, nil, err
+		}
+		return g.Polygons, nil, nil
+	case "wkb":
+		g, err := wkt.ParseHex(string(input))
+		if err != nil {
+			return nil, nil, err
+		}
+		return g.Polygons, nil, nil
+	}
+	return nil, nil, fmt.Errorf("%w %q", errUnknownFormat, format)
+}
+
+// guess the format from the first characters of the input
+func detectFormat(input []byte) string {
+	trimmed := bytes.TrimSpace(input)
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("[")):
+		return "json"
+	case bytes.HasPrefix(trimmed, []byte("{")):
+		var probe struct {
+			Type *string `json:"type"`
+		}
+		if json.Unmarshal(trimmed, &probe) == nil && probe.Type != nil {
+			return "geojson"
+		}
+		return "json"
+	case len(trimmed) > 0 && strings.Trim(string(trimmed), "0123456789abcdefABCDEF") == "":
+		return "wkb"
+	}
+	return "wkt"
+}
+
+// read Earcut's flat form or an array of rings
+func parseJSON(input []byte) ([]earcut.Polygon, error) {
+	trimmed := bytes.TrimSpace(input)
+	if bytes.HasPrefix(trimmed, []byte("[")) {
+		var rings [][][]float64
+		if err := json.Unmarshal(trimmed, &rings); err != nil {
+			return nil, err
+		}
+		if len(rings) == 0 || len(rings[0]) == 0 {
+			return nil, nil
+		}
+		data, holes, dim := earcut.Flatten(rings)
+		return []earcut.Polygon{{Data: data, Holes: holes, Dim: dim}}, nil
+	}
+
+	var flat struct {
+		Vertices   []float64 `json:"vertices"`
+		Holes      []int     `json:"holes"`
+		Dimensions int       `json:"dimensions"`
+	}
+	if err := json.Unmarshal(trimmed, &flat); err != nil {
+		return nil, err
+	}
+	if flat.Dimensions == 0 {
+		flat.Dimensions = 2
+	}
+	return []earcut.Polygon{{Data: flat.Vertices, Holes: flat.Holes, Dim: flat.Dimensions}}, nil
+}
+
+// triangulate every polygon into one vertex array, reporting the worst deviation
+func triangulate(polygons []earcut.Polygon) (*result, error) {
+	res := &result{Triangles: []int{}, Vertices: []float64{}}
+	for k, p := range polygons {
+		if res.Dimensions == 0 {
+			res.Dimensions = p.Dim
+		}
+		if p.Dim != res.Dimensions {
+			return nil, fmt.Errorf("polygon %d has %d dimensions, expected %d", k, p.Dim, res.Dimensions)
+		}
+
+		triangles, err := earcut.EarcutE(p.Data, p.Holes, p.Dim)
+		if err != nil {
+			return nil, fmt.Errorf("polygon %d: %w", k, err)
+		}
+		res.Deviation = deviation(math.Max(float64(res.Deviation), earcut.Deviation(p.Data, p.Holes, p.Dim, triangles)))
+
+		res.meshes = append(res.meshes, export.Mesh{Vertices: p.Data, Dim: p.Dim, Triangles: triangles})
+		offset := len(res.Vertices) / p.Dim
+		res.Vertices = append(res.Vertices, p.Data...)
+		for _, i := range triangles {
+			res.Triangles = append(res.Triangles, i+offset)
+		}
+	}
+	if res.Dimensions == 0 {
+		res.Dimensions = 2
+	}
+	return res, nil
+}
+
+// name each of n polygons after its feature, or after its index when it has no feature name
+func polygonNames(features []string, n int) []string {
+	names := make([]string, n)
+	for k := range names {
+		if k < len(features) && features[k] != "" {
+			names[k] = features[k]
+		} else {
+			names[k] = "polygon " + strconv.Itoa(k)
+		}
+	}
+	return names
+}
+
+func writeFile(path string, write func(io.Writer) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	if err := write(w); err != nil {
+		f.Close()
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,91 @@
+package earcut
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// BatchOptions configures TriangulateBatch.
+type BatchOptions struct {
+	// Workers is the number of goroutines triangulating at once; 0 means runtime.GOMAXPROCS(0).
+	Workers int
+	// Options applies to every polygon; MaxWork is a budget per polygon.
+	Options Options
+}
+
+// BatchResult is the outcome for one polygon of a batch.
+type BatchResult struct {
+	// Triangles holds the triangle indices into the polygon's own Data, partial when Err is set.
+	Triangles []int
+	// Err is the error EarcutContext would have returned for the polygon.
+	Err error
+}
+
+// BatchStats sums up a batch.
+type BatchStats struct {
+	// Polygons is the number of polygons and Failed the number of them with an error.
+	Polygons, Failed int
+	// Vertices and Triangles count the input vertices and the output triangles.
+	Vertices, Triangles int
+	// Elapsed is the time the whole batch took; Busy adds up the time each worker spent
+	// triangulating, so Busy/Elapsed tells how many workers were kept busy on average.
+	Elapsed, Busy time.Duration
+}
+
+// TriangulateBatch triangulates independent polygons on a pool of workers, each with its own
+// Triangulator so node storage is reused across the polygons it takes. Results are returned in
+// the order of polygons. Once ctx is done, the polygons not yet finished get ctx.Err().
+func TriangulateBatch(ctx context.Context, polygons []Polygon, opts BatchOptions) ([]BatchResult, BatchStats) {
+	start := time.Now()
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	workers = clamp(workers, 1, len(polygons))
+
+	results := make([]BatchResult, len(polygons))
+	busy := make([]time.Duration, workers)
+	var next atomic.Int64
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			began := time.Now()
+			t := Triangulator{Options: opts.Options}
+			for {
+				i := int(next.Add(1) - 1)
+				if i >= len(polygons) {
+					break
+				}
+				p := polygons[i]
+				results[i].Triangles, results[i].Err = t.TriangulateContext(ctx, []int{}, p.Data, p.Holes, p.Dim)
+			}
+			busy[w] = time.Since(began)
+		}(w)
+	}
+	wg.Wait()
+
+	stats := BatchStats{Polygons: len(polygons)}
+	for i, r := range results {
+		if r.Err != nil {
+			stats.Failed++
+		}
+		dim := polygons[i].Dim
+		if dim == 0 {
+			dim = 2
+		}
+		if dim > 0 {
+			stats.Vertices += len(polygons[i].Data) / dim
+		}
+		stats.Triangles += len(r.Triangles) / 3
+	}
+	for _, b := range busy {
+		stats.Busy += b
+	}
+	stats.Elapsed = time.Since(start)
+	return results, stats
+}
@@ -0,0 +1,53 @@
+package earcut
+
+import (
+	"context"
+	"fmt"
+)
+
+// how many nodes the inner loops visit between checks of the context; counting visits rather
+// than steps bounds the delay even when single steps walk the whole polygon
+const ctxCheckInterval = 1024
+
+// EarcutContext is like EarcutE with options, but stops early when ctx is done or opts.MaxWork
+// steps have been taken. It then returns the triangles found so far, which cover only part of
+// the polygon, together with ctx.Err() or an error wrapping ErrBudgetExceeded.
+func EarcutContext(ctx context.Context, data []float64, holeIndices []int, dim int, opts Options) ([]int, error) {
+	var t Triangulator
+	t.Options = opts
+	return t.TriangulateContext(ctx, []int{}, data, holeIndices, dim)
+}
+
+// TriangulateContext is like Triangulate but validates the input like EarcutE and stops early
+// like EarcutContext, returning dst extended with the triangles found so far and the reason.
+func (t *Triangulator) TriangulateContext(ctx context.Context, dst []int, data []float64, holes []int, dim int) ([]int, error) {
+	if dim == 0 {
+		dim = 2
+	}
+	if err := checkInput(data, holes, dim); err != nil {
+		return dst, err
+	}
+	t.e.opts = t.Options
+	t.e.ctx = ctx
+	dst = run(&t.e, dst, data, holes, dim)
+	return dst, t.e.err
+}
+
+// count one step of work, returning false once the run has to stop
+func (e *earcutter) step() bool {
+	if e.err != nil {
+		return false
+	}
+	e.work++
+	if e.opts.MaxWork > 0 && e.work > e.opts.MaxWork {
+		e.err = fmt.Errorf("%w: limit is %d steps", ErrBudgetExceeded, e.opts.MaxWork)
+		return false
+	}
+	e.ticks++
+	if e.ctx != nil && e.ticks >= ctxCheckInterval {
+		e.ticks = 0
+		e.err = e.ctx.Err()
+		return e.err == nil
+	}
+	return true
+}
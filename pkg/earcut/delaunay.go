@@ -0,0 +1,112 @@
+package earcut
+
+import "math"
+
+// bound on the relative error of the incircle determinant, from Shewchuk's predicates
+const iccErrBoundA = (10 + 96*epsilon) * epsilon
+
+// RefineDelaunay flips the diagonals of triangles produced by Earcut for the same data,
+// holeIndices and dim until the mesh is constrained Delaunay: no triangle's circumcircle
+// contains a vertex it can see across an edge. Ring edges are never flipped, so the outline
+// and holes are kept. Triangles are rewritten in place, using x and y only, and the number of
+// flips is returned. Use MinAngle to measure the improvement.
+func RefineDelaunay(data []float64, holeIndices []int, dim int, triangles []int) int {
+	if dim == 0 {
+		dim = 2
+	}
+	halfedges := Halfedges(triangles)
+
+	// unpair the ring edges so they are never flipped
+	constrained := ringEdges(len(data)/dim, holeIndices)
+	for e, twin := range halfedges {
+		if twin >= 0 && constrained[edgeKey(triangles[e], triangles[NextHalfedge(e)])] {
+			halfedges[e] = -1
+			halfedges[twin] = -1
+		}
+	}
+
+	m := &mesh{data: data, dim: dim, triangles: triangles, halfedges: halfedges}
+	for e := len(triangles) - 1; e >= 0; e-- {
+		if halfedges[e] > e {
+			m.stack = append(m.stack, e)
+		}
+	}
+	return m.legalize()
+}
+
+func edgeKey(i, j int) [2]int {
+	if i > j {
+		i, j = j, i
+	}
+	return [2]int{i, j}
+}
+
+// the edges of every ring of a polygon with n vertices
+func ringEdges(n int, holeIndices []int) map[[2]int]bool {
+	edges := make(map[[2]int]bool, n)
+	starts := append([]int{0}, holeIndices...)
+	for r, start := range starts {
+		end := n
+		if r+1 < len(starts) {
+			end = starts[r+1]
+		}
+		for i := start; i < end; i++ {
+			j := i + 1
+			if j == end {
+				j = start
+			}
+			edges[edgeKey(i, j)] = true
+		}
+	}
+	return edges
+}
+
+// whether d is certainly inside the circumcircle of the counterclockwise triangle abc;
+// points too close to the circle to tell count as outside, which keeps flipping from cycling
+func inCircle(ax, ay, bx, by, cx, cy, dx, dy float64) bool {
+	adx, ady := ax-dx, ay-dy
+	bdx, bdy := bx-dx, by-dy
+	cdx, cdy := cx-dx, cy-dy
+
+	alift := adx*adx + ady*ady
+	blift := bdx*bdx + bdy*bdy
+	clift := cdx*cdx + cdy*cdy
+
+	det := alift*(bdx*cdy-bdy*cdx) + blift*(cdx*ady-cdy*adx) + clift*(adx*bdy-ady*bdx)
+	permanent := alift*(math.Abs(bdx*cdy)+math.Abs(bdy*cdx)) +
+		blift*(math.Abs(cdx*ady)+math.Abs(cdy*adx)) +
+		clift*(math.Abs(adx*bdy)+math.Abs(ady*bdx))
+	return det > iccErrBoundA*permanent
+}
+
+// whether the quadrilateral abcd is strictly convex, so that its diagonals cross
+func convexQuad(ax, ay, bx, by, cx, cy, dx, dy float64) bool {
+	return Orient2D(ax, ay, bx, by, cx, cy) > 0 && Orient2D(bx, by, cx, cy, dx, dy) > 0 &&
+		Orient2D(cx, cy, dx, dy, ax, ay) > 0 && Orient2D(dx, dy, ax, ay, bx, by) > 0
+}
+
+// MinAngle returns the smallest interior angle, in degrees, of the triangles over data
+// (dim coordinates per vertex, x and y used), or 0 if there are none or one is degenerate.
+func MinAngle(data []float64, dim int, triangles []int) float64 {
+	if dim == 0 {
+		dim = 2
+	}
+	if len(triangles) < 3 {
+		return 0
+	}
+	minAngle := 180.0
+	for t := 0; t+2 < len(triangles); t += 3 {
+		for k := 0; k < 3; k++ {
+			p := triangles[t+k] * dim
+			q := triangles[t+(k+1)%3] * dim
+			r := triangles[t+(k+2)%3] * dim
+			ux, uy := data[q]-data[p], data[q+1]-data[p+1]
+			vx, vy := data[r]-data[p], data[r+1]-data[p+1]
+			angle := math.Abs(math.Atan2(ux*vy-uy*vx, ux*vx+uy*vy)) * 180 / math.Pi
+			if angle < minAngle {
+				minAngle = angle
+			}
+		}
+	}
+	return minAngle
+}
@@ -3,8 +3,9 @@
 package earcut
 
 import (
+	"context"
 	"math"
-	"sort"
+	"slices"
 )
 
 // Earcut triangulates the given polygon with optional holes.
@@ -14,9 +15,40 @@ import (
 // dim is the number of coordinates per vertex in the input array (2 by default).
 // Returns a flat array of triangle indices like [a,b,c, d,e,f, ...].
 func Earcut(data []float64, holeIndices []int, dim int) []int {
+	return EarcutWithOptions(data, holeIndices, dim, Options{})
+}
+
+// EarcutWithOptions is like Earcut but lets opts tune z-order hashing and the fallback passes.
+// opts.MaxWork is ignored, since there is no error to report the cut-off with; use EarcutContext
+// for a budget.
+func EarcutWithOptions(data []float64, holeIndices []int, dim int, opts Options) []int {
+	opts.MaxWork = 0
+	e := &earcutter{opts: opts}
+	return run(e, []int{}, data, holeIndices, dim)
+}
+
+// run triangulates the polygon, appending the triangle indices to triangles
+func run[F Float](e *earcutter, triangles []int, data []F, holeIndices []int, dim int) []int {
 	if dim == 0 {
 		dim = 2
 	}
+	if e.opts.ProjectToPlane && dim >= 3 {
+		e.projected = projectToPlane(e.projected[:0], data, holeIndices, dim)
+		return run(e, triangles, e.projected, holeIndices, 2)
+	}
+
+	e.triangles = triangles
+	e.minX, e.minY, e.invSize = 0, 0, 0
+	e.work, e.ticks, e.err = 0, 0, nil
+	// don't hold on to the caller's buffer or context once the triangles have been handed back
+	defer func() { e.triangles, e.ctx = nil, nil }()
+
+	if e.ctx != nil {
+		if e.err = e.ctx.Err(); e.err != nil {
+			return e.triangles
+		}
+	}
+	e.nodes.reset(len(data)/dim + 2*len(holeIndices))
 
 	hasHoles := holeIndices != nil && len(holeIndices) > 0
 	outerLen := 0
@@ -26,34 +58,31 @@ func Earcut(data []float64, holeIndices []int, dim int) []int {
 		outerLen = len(data)
 	}
 
-	outerNode := linkedList(data, 0, outerLen, dim, true)
-	triangles := []int{}
+	outerNode := linkedList(e, data, 0, outerLen, dim, true)
 
 	if outerNode == nil || outerNode.next == outerNode.prev {
-		return triangles
+		return e.triangles
 	}
 
-	var minX, minY, invSize float64
-
 	if hasHoles {
-		outerNode = eliminateHoles(data, holeIndices, outerNode, dim)
+		outerNode = eliminateHoles(e, data, holeIndices, outerNode, dim)
 	}
 
 	// if the shape is not too simple, we'll use z-order curve hash later; calculate polygon bbox
-	if len(data) > 80*dim {
-		minX = data[0]
-		minY = data[1]
-		maxX := data[0]
-		maxY := data[1]
+	if e.useHashing(len(data) / dim) {
+		e.minX = float64(data[0])
+		e.minY = float64(data[1])
+		maxX := e.minX
+		maxY := e.minY
 
 		for i := dim; i < outerLen; i += dim {
-			x := data[i]
-			y := data[i+1]
-			if x < minX {
-				minX = x
+			x := float64(data[i])
+			y := float64(data[i+1])
+			if x < e.minX {
+				e.minX = x
 			}
-			if y < minY {
-				minY = y
+			if y < e.minY {
+				e.minY = y
 			}
 			if x > maxX {
 				maxX = x
@@ -64,17 +93,37 @@ func Earcut(data []float64, holeIndices []int, dim int) []int {
 		}
 
 		// minX, minY and invSize are later used to transform coords into integers for z-order calculation
-		invSize = 0
-		if maxX-minX > maxY-minY {
-			invSize = 32767 / (maxX - minX)
-		} else {
-			invSize = 32767 / (maxY - minY)
+		size := max(maxX-e.minX, maxY-e.minY)
+		if size != 0 {
+			e.invSize = 32767 / size
 		}
 	}
 
-	earcutLinked(outerNode, &triangles, dim, minX, minY, invSize, 0)
+	e.earcutLinked(outerNode, 0)
 
-	return triangles
+	return e.triangles
+}
+
+// earcutter holds the settings, z-order transform and output shared by the passes of one triangulation
+type earcutter struct {
+	opts Options
+	// minX, minY and invSize map coordinates to z-order; invSize is 0 when hashing is off
+	minX, minY, invSize float64
+	triangles           []int
+	// node storage and hole queue, kept between runs
+	nodes nodePool
+	queue []*Node
+	// 2D coordinates of the vertices when Options.ProjectToPlane is set
+	projected []float64
+	// ctx, if not nil, cancels the run; work counts steps against Options.MaxWork, ticks counts
+	// the nodes visited since ctx was last checked and err records why the run stopped early
+	ctx   context.Context
+	work  int
+	ticks int
+	err   error
+	// bridges collects the hole bridges when traceBridges is set
+	traceBridges bool
+	bridges      [][2]int
 }
 
 // Node represents a vertex in a doubly-linked list
@@ -94,16 +143,20 @@ type Node struct {
 }
 
 // create a circular doubly linked list from polygon points in the specified winding order
-func linkedList(data []float64, start, end, dim int, clockwise bool) *Node {
+func linkedList[F Float](e *earcutter, data []F, start, end, dim int, clockwise bool) *Node {
 	var last *Node
 
-	if clockwise == (signedArea(data, start, end, dim) > 0) {
+	area := signedArea(data, start, end, dim)
+	if e.opts.Robust {
+		area = robustSignedArea(data, start, end, dim)
+	}
+	if clockwise == (area > 0) {
 		for i := start; i < end; i += dim {
-			last = insertNode(i/dim, data[i], data[i+1], last)
+			last = e.insertNode(i/dim, float64(data[i]), float64(data[i+1]), last)
 		}
 	} else {
 		for i := end - dim; i >= start; i -= dim {
-			last = insertNode(i/dim, data[i], data[i+1], last)
+			last = e.insertNode(i/dim, float64(data[i]), float64(data[i+1]), last)
 		}
 	}
 
@@ -116,12 +169,8 @@ func linkedList(data []float64, start, end, dim int, clockwise bool) *Node {
 }
 
 // create a node and optionally link it with previous one (in a circular doubly linked list)
-func insertNode(i int, x, y float64, last *Node) *Node {
-	p := &Node{
-		i: i,
-		x: x,
-		y: y,
-	}
+func (e *earcutter) insertNode(i int, x, y float64, last *Node) *Node {
+	p := e.createNode(i, x, y)
 
 	if last == nil {
 		p.prev = p
@@ -147,8 +196,11 @@ func removeNode(p *Node) {
 	}
 }
 
-// signed area of a triangle
-func area(p, q, r *Node) float64 {
+// signed area of a triangle, positive when p, q and r are clockwise
+func (e *earcutter) area(p, q, r *Node) float64 {
+	if e.opts.Robust {
+		return -Orient2D(p.x, p.y, q.x, q.y, r.x, r.y)
+	}
 	return (q.y-p.y)*(r.x-q.x) - (q.x-p.x)*(r.y-q.y)
 }
 
@@ -158,17 +210,17 @@ func equals(p1, p2 *Node) bool {
 }
 
 // signed area of a polygon
-func signedArea(data []float64, start, end, dim int) float64 {
+func signedArea[F Float](data []F, start, end, dim int) float64 {
 	var sum float64
 	for i, j := start, end-dim; i < end; i += dim {
-		sum += (data[j] - data[i]) * (data[i+1] + data[j+1])
+		sum += (float64(data[j]) - float64(data[i])) * (float64(data[i+1]) + float64(data[j+1]))
 		j = i
 	}
 	return sum
 }
 
 // eliminate colinear or duplicate points
-func filterPoints(start, end *Node) *Node {
+func (e *earcutter) filterPoints(start, end *Node) *Node {
 	if start == nil {
 		return start
 	}
@@ -180,12 +232,13 @@ func filterPoints(start, end *Node) *Node {
 	var again bool
 
 	for {
+		e.ticks++
 		again = false
 
-		if !p.steiner && (equals(p, p.next) || area(p.prev, p, p.next) == 0) {
+		if !p.steiner && (equals(p, p.next) || e.area(p.prev, p, p.next) == 0) {
 			removeNode(p)
-			p = end
 			p = p.prev
+			end = p
 			if p == p.next {
 				break
 			}
@@ -203,33 +256,36 @@ func filterPoints(start, end *Node) *Node {
 }
 
 // main ear slicing loop which triangulates a polygon (given as a linked list)
-func earcutLinked(ear *Node, triangles *[]int, dim int, minX, minY, invSize float64, pass int) {
+func (e *earcutter) earcutLinked(ear *Node, pass int) {
 	if ear == nil {
 		return
 	}
 
 	// interlink polygon nodes in z-order
-	if pass == 0 && invSize != 0 {
-		indexCurve(ear, minX, minY, invSize)
+	if pass == 0 && e.invSize != 0 {
+		indexCurve(ear, e.minX, e.minY, e.invSize)
 	}
 
 	stop := ear
 
 	// iterate through ears, slicing them one by one
 	for ear.prev != ear.next {
+		if !e.step() {
+			return
+		}
 		prev := ear.prev
 		next := ear.next
 
 		var isEarValid bool
-		if invSize != 0 {
-			isEarValid = isEarHashed(ear, minX, minY, invSize)
+		if e.invSize != 0 {
+			isEarValid = e.isEarHashed(ear, e.minX, e.minY, e.invSize)
 		} else {
-			isEarValid = isEar(ear)
+			isEarValid = e.isEar(ear)
 		}
 
 		if isEarValid {
 			// cut off the triangle
-			*triangles = append(*triangles, prev.i, ear.i, next.i)
+			e.triangles = append(e.triangles, prev.i, ear.i, next.i)
 
 			removeNode(ear)
 
@@ -244,16 +300,23 @@ func earcutLinked(ear *Node, triangles *[]int, dim int, minX, minY, invSize floa
 
 		// if we looped through the whole remaining polygon and can't find any more ears
 		if ear == stop {
+			// stop here if the caller capped the passes below the next one
+			if !e.passEnabled(pass + 1) {
+				break
+			}
 			// try filtering points and slicing again
 			if pass == 0 {
-				earcutLinked(filterPoints(ear, nil), triangles, dim, minX, minY, invSize, 1)
+				e.earcutLinked(e.filterPoints(ear, nil), 1)
 			} else if pass == 1 {
 				// if this didn't work, try curing all small self-intersections locally
-				ear = cureLocalIntersections(filterPoints(ear, nil), triangles)
-				earcutLinked(ear, triangles, dim, minX, minY, invSize, 2)
+				ear = e.filterPoints(ear, nil)
+				if !e.opts.DisableCure {
+					ear = e.cureLocalIntersections(ear)
+				}
+				e.earcutLinked(ear, 2)
 			} else if pass == 2 {
 				// as a last resort, try splitting the remaining polygon into two
-				splitEarcut(ear, triangles, dim, minX, minY, invSize)
+				e.splitEarcut(ear)
 			}
 			break
 		}
@@ -261,12 +324,12 @@ func earcutLinked(ear *Node, triangles *[]int, dim int, minX, minY, invSize floa
 }
 
 // check whether a polygon node forms a valid ear with adjacent nodes
-func isEar(ear *Node) bool {
+func (e *earcutter) isEar(ear *Node) bool {
 	a := ear.prev
 	b := ear
 	c := ear.next
 
-	if area(a, b, c) >= 0 {
+	if e.area(a, b, c) >= 0 {
 		return false // reflex, can't be an ear
 	}
 
@@ -283,10 +346,11 @@ func isEar(ear *Node) bool {
 	p := c.next
 	for p != a {
 		if p.x >= x0 && p.x <= x1 && p.y >= y0 && p.y <= y1 &&
-			pointInTriangleExceptFirst(ax, ay, bx, by, cx, cy, p.x, p.y) &&
-			area(p.prev, p, p.next) >= 0 {
+			e.pointInTriangleExceptFirst(ax, ay, bx, by, cx, cy, p.x, p.y) &&
+			e.area(p.prev, p, p.next) >= 0 {
 			return false
 		}
+		e.ticks++
 		p = p.next
 	}
 
@@ -316,24 +380,29 @@ func max(a, b float64) float64 {
 }
 
 // check if a point lies within a convex triangle
-func pointInTriangle(ax, ay, bx, by, cx, cy, px, py float64) bool {
+func (e *earcutter) pointInTriangle(ax, ay, bx, by, cx, cy, px, py float64) bool {
+	if e.opts.Robust {
+		return Orient2D(px, py, cx, cy, ax, ay) >= 0 &&
+			Orient2D(px, py, ax, ay, bx, by) >= 0 &&
+			Orient2D(px, py, bx, by, cx, cy) >= 0
+	}
 	return (cx-px)*(ay-py) >= (ax-px)*(cy-py) &&
 		(ax-px)*(by-py) >= (bx-px)*(ay-py) &&
 		(bx-px)*(cy-py) >= (cx-px)*(by-py)
 }
 
 // check if a point lies within a convex triangle but false if its equal to the first point of the triangle
-func pointInTriangleExceptFirst(ax, ay, bx, by, cx, cy, px, py float64) bool {
-	return !(ax == px && ay == py) && pointInTriangle(ax, ay, bx, by, cx, cy, px, py)
+func (e *earcutter) pointInTriangleExceptFirst(ax, ay, bx, by, cx, cy, px, py float64) bool {
+	return !(ax == px && ay == py) && e.pointInTriangle(ax, ay, bx, by, cx, cy, px, py)
 }
 
 // check whether a polygon node forms a valid ear with adjacent nodes
-func isEarHashed(ear *Node, minX, minY, invSize float64) bool {
+func (e *earcutter) isEarHashed(ear *Node, minX, minY, invSize float64) bool {
 	a := ear.prev
 	b := ear
 	c := ear.next
 
-	if area(a, b, c) >= 0 {
+	if e.area(a, b, c) >= 0 {
 		return false // reflex, can't be an ear
 	}
 
@@ -356,33 +425,37 @@ func isEarHashed(ear *Node, minX, minY, invSize float64) bool {
 	// look for points inside the triangle in both directions
 	for p != nil && p.z >= minZ && n != nil && n.z <= maxZ {
 		if p.x >= x0 && p.x <= x1 && p.y >= y0 && p.y <= y1 && p != a && p != c &&
-			pointInTriangleExceptFirst(ax, ay, bx, by, cx, cy, p.x, p.y) && area(p.prev, p, p.next) >= 0 {
+			e.pointInTriangleExceptFirst(ax, ay, bx, by, cx, cy, p.x, p.y) && e.area(p.prev, p, p.next) >= 0 {
 			return false
 		}
+		e.ticks++
 		p = p.prevZ
 
 		if n.x >= x0 && n.x <= x1 && n.y >= y0 && n.y <= y1 && n != a && n != c &&
-			pointInTriangleExceptFirst(ax, ay, bx, by, cx, cy, n.x, n.y) && area(n.prev, n, n.next) >= 0 {
+			e.pointInTriangleExceptFirst(ax, ay, bx, by, cx, cy, n.x, n.y) && e.area(n.prev, n, n.next) >= 0 {
 			return false
 		}
+		e.ticks++
 		n = n.nextZ
 	}
 
 	// look for remaining points in decreasing z-order
 	for p != nil && p.z >= minZ {
 		if p.x >= x0 && p.x <= x1 && p.y >= y0 && p.y <= y1 && p != a && p != c &&
-			pointInTriangleExceptFirst(ax, ay, bx, by, cx, cy, p.x, p.y) && area(p.prev, p, p.next) >= 0 {
+			e.pointInTriangleExceptFirst(ax, ay, bx, by, cx, cy, p.x, p.y) && e.area(p.prev, p, p.next) >= 0 {
 			return false
 		}
+		e.ticks++
 		p = p.prevZ
 	}
 
 	// look for remaining points in increasing z-order
 	for n != nil && n.z <= maxZ {
 		if n.x >= x0 && n.x <= x1 && n.y >= y0 && n.y <= y1 && n != a && n != c &&
-			pointInTriangleExceptFirst(ax, ay, bx, by, cx, cy, n.x, n.y) && area(n.prev, n, n.next) >= 0 {
+			e.pointInTriangleExceptFirst(ax, ay, bx, by, cx, cy, n.x, n.y) && e.area(n.prev, n, n.next) >= 0 {
 			return false
 		}
+		e.ticks++
 		n = n.nextZ
 	}
 
@@ -390,14 +463,17 @@ func isEarHashed(ear *Node, minX, minY, invSize float64) bool {
 }
 
 // go through all polygon nodes and cure small local self-intersections
-func cureLocalIntersections(start *Node, triangles *[]int) *Node {
+func (e *earcutter) cureLocalIntersections(start *Node) *Node {
 	p := start
 	for {
+		if !e.step() {
+			return p
+		}
 		a := p.prev
 		b := p.next.next
 
-		if !equals(a, b) && intersects(a, p, p.next, b) && locallyInside(a, b) && locallyInside(b, a) {
-			*triangles = append(*triangles, a.i, p.i, b.i)
+		if !equals(a, b) && e.intersects(a, p, p.next, b) && e.locallyInside(a, b) && e.locallyInside(b, a) {
+			e.triangles = append(e.triangles, a.i, p.i, b.i)
 
 			// remove two nodes involved
 			removeNode(p)
@@ -412,27 +488,30 @@ func cureLocalIntersections(start *Node, triangles *[]int) *Node {
 		}
 	}
 
-	return filterPoints(p, nil)
+	return e.filterPoints(p, nil)
 }
 
 // try splitting polygon into two and triangulate them independently
-func splitEarcut(start *Node, triangles *[]int, dim int, minX, minY, invSize float64) {
+func (e *earcutter) splitEarcut(start *Node) {
 	// look for a valid diagonal that divides the polygon into two
 	a := start
 	for {
 		b := a.next.next
 		for b != a.prev {
-			if a.i != b.i && isValidDiagonal(a, b) {
+			if !e.step() {
+				return
+			}
+			if a.i != b.i && e.isValidDiagonal(a, b) {
 				// split the polygon in two by the diagonal
-				c := splitPolygon(a, b)
+				c := e.splitPolygon(a, b)
 
 				// filter colinear points around the cuts
-				a = filterPoints(a, a.next)
-				c = filterPoints(c, c.next)
+				a = e.filterPoints(a, a.next)
+				c = e.filterPoints(c, c.next)
 
 				// run earcut on each half
-				earcutLinked(a, triangles, dim, minX, minY, invSize, 0)
-				earcutLinked(c, triangles, dim, minX, minY, invSize, 0)
+				e.earcutLinked(a, 0)
+				e.earcutLinked(c, 0)
 				return
 			}
 			b = b.next
@@ -445,8 +524,8 @@ func splitEarcut(start *Node, triangles *[]int, dim int, minX, minY, invSize flo
 }
 
 // link every hole into the outer loop, producing a single-ring polygon without holes
-func eliminateHoles(data []float64, holeIndices []int, outerNode *Node, dim int) *Node {
-	queue := []*Node{}
+func eliminateHoles[F Float](e *earcutter, data []F, holeIndices []int, outerNode *Node, dim int) *Node {
+	queue := e.queue[:0]
 
 	for i, length := 0, len(holeIndices); i < length; i++ {
 		start := holeIndices[i] * dim
@@ -456,7 +535,7 @@ func eliminateHoles(data []float64, holeIndices []int, outerNode *Node, dim int)
 		} else {
 			end = len(data)
 		}
-		list := linkedList(data, start, end, dim, false)
+		list := linkedList(e, data, start, end, dim, false)
 		if list == list.next {
 			list.steiner = true
 		}
@@ -467,47 +546,63 @@ func eliminateHoles(data []float64, holeIndices []int, outerNode *Node, dim int)
 	sortByXYSlope(queue)
 
 	// process holes from left to right
-	for i := 0; i < len(queue); i++ {
-		outerNode = eliminateHole(queue[i], outerNode)
+	for i := 0; i < len(queue) && e.step(); i++ {
+		outerNode = e.eliminateHole(queue[i], outerNode)
 	}
 
+	clear(queue)
+	e.queue = queue
+
 	return outerNode
 }
 
 // sort an array of nodes by x, then y, then slope
 func sortByXYSlope(nodes []*Node) {
-	// Sort using Go's sort package
-	sort.Slice(nodes, func(i, j int) bool {
-		a, b := nodes[i], nodes[j]
+	// slices.SortFunc doesn't allocate, unlike sort.Slice
+	slices.SortFunc(nodes, func(a, b *Node) int {
 		if a.x != b.x {
-			return a.x < b.x
+			return compareFloat(a.x, b.x)
 		}
 		if a.y != b.y {
-			return a.y < b.y
+			return compareFloat(a.y, b.y)
 		}
 		// when two holes' leftmost points are at the same vertex, sort counterclockwise
 		aSlope := (a.next.y - a.y) / (a.next.x - a.x)
 		bSlope := (b.next.y - b.y) / (b.next.x - b.x)
-		return aSlope < bSlope
+		return compareFloat(aSlope, bSlope)
 	})
 }
 
+// three-way comparison that, unlike cmp.Compare, treats NaN as equal to everything
+func compareFloat(a, b float64) int {
+	if a < b {
+		return -1
+	}
+	if a > b {
+		return 1
+	}
+	return 0
+}
+
 // find a bridge between vertices that connects hole with an outer ring and link it
-func eliminateHole(hole, outerNode *Node) *Node {
-	bridge := findHoleBridge(hole, outerNode)
+func (e *earcutter) eliminateHole(hole, outerNode *Node) *Node {
+	bridge := e.findHoleBridge(hole, outerNode)
 	if bridge == nil {
 		return outerNode
 	}
 
-	bridgeReverse := splitPolygon(bridge, hole)
+	if e.traceBridges {
+		e.bridges = append(e.bridges, [2]int{bridge.i, hole.i})
+	}
+	bridgeReverse := e.splitPolygon(bridge, hole)
 
 	// filter collinear points around the cuts
-	filterPoints(bridgeReverse, bridgeReverse.next)
-	return filterPoints(bridge, bridge.next)
+	e.filterPoints(bridgeReverse, bridgeReverse.next)
+	return e.filterPoints(bridge, bridge.next)
 }
 
 // David Eberly's algorithm for finding a bridge between hole and outer polygon
-func findHoleBridge(hole, outerNode *Node) *Node {
+func (e *earcutter) findHoleBridge(hole, outerNode *Node) *Node {
 	p := outerNode
 	hx := hole.x
 	hy := hole.y
@@ -537,6 +632,7 @@ func findHoleBridge(hole, outerNode *Node) *Node {
 				}
 			}
 		}
+		e.ticks++
 		p = p.next
 		if p == outerNode {
 			break
@@ -560,17 +656,18 @@ func findHoleBridge(hole, outerNode *Node) *Node {
 
 	for {
 		if hx >= p.x && p.x >= mx && hx != p.x &&
-			pointInTriangle(hx, hy, qx, hy, mx, my, p.x, p.y) {
+			e.bridgeTriangleContains(hx, hy, qx, mx, my, p.x, p.y) {
 
 			tan := math.Abs(hy-p.y) / (hx - p.x) // tangential
 
-			if locallyInside(p, hole) &&
-				(tan < tanMin || (tan == tanMin && (p.x > m.x || (p.x == m.x && sectorContainsSector(m, p))))) {
+			if e.locallyInside(p, hole) &&
+				(tan < tanMin || (tan == tanMin && (p.x > m.x || (p.x == m.x && e.sectorContainsSector(m, p))))) {
 				m = p
 				tanMin = tan
 			}
 		}
 
+		e.ticks++
 		p = p.next
 		if p == stop {
 			break
@@ -580,9 +677,19 @@ func findHoleBridge(hole, outerNode *Node) *Node {
 	return m
 }
 
+// whether p lies in the triangle of the hole point (hx, hy), the ray's hit (qx, hy) and the
+// bridge candidate (mx, my), taking its corners in the order pointInTriangle expects, which
+// depends on whether the candidate lies above or below the ray
+func (e *earcutter) bridgeTriangleContains(hx, hy, qx, mx, my, px, py float64) bool {
+	if hy < my {
+		return e.pointInTriangle(hx, hy, mx, my, qx, hy, px, py)
+	}
+	return e.pointInTriangle(qx, hy, mx, my, hx, hy, px, py)
+}
+
 // whether sector in vertex m contains sector in vertex p in the same coordinates
-func sectorContainsSector(m, p *Node) bool {
-	return area(m.prev, m, p.prev) < 0 && area(p.next, m, m.next) < 0
+func (e *earcutter) sectorContainsSector(m, p *Node) bool {
+	return e.area(m.prev, m, p.prev) < 0 && e.area(p.next, m, m.next) < 0
 }
 
 // interlink polygon nodes in z-order
@@ -704,19 +811,19 @@ func getLeftmost(start *Node) *Node {
 }
 
 // check if a diagonal between two polygon nodes is valid (lies in polygon interior)
-func isValidDiagonal(a, b *Node) bool {
-	return a.next.i != b.i && a.prev.i != b.i && !intersectsPolygon(a, b) && // doesn't intersect other edges
-		(locallyInside(a, b) && locallyInside(b, a) && middleInside(a, b) && // locally visible
-			(area(a.prev, a, b.prev) != 0 || area(a, b.prev, b) != 0) || // does not create opposite-facing sectors
-			equals(a, b) && area(a.prev, a, a.next) > 0 && area(b.prev, b, b.next) > 0) // special zero-length case
+func (e *earcutter) isValidDiagonal(a, b *Node) bool {
+	return a.next.i != b.i && a.prev.i != b.i && !e.intersectsPolygon(a, b) && // doesn't intersect other edges
+		(e.locallyInside(a, b) && e.locallyInside(b, a) && e.middleInside(a, b) && // locally visible
+			(e.area(a.prev, a, b.prev) != 0 || e.area(a, b.prev, b) != 0) || // does not create opposite-facing sectors
+			equals(a, b) && e.area(a.prev, a, a.next) > 0 && e.area(b.prev, b, b.next) > 0) // special zero-length case
 }
 
 // check if two segments intersect
-func intersects(p1, q1, p2, q2 *Node) bool {
-	o1 := sign(area(p1, q1, p2))
-	o2 := sign(area(p1, q1, q2))
-	o3 := sign(area(p2, q2, p1))
-	o4 := sign(area(p2, q2, q1))
+func (e *earcutter) intersects(p1, q1, p2, q2 *Node) bool {
+	o1 := sign(e.area(p1, q1, p2))
+	o2 := sign(e.area(p1, q1, q2))
+	o3 := sign(e.area(p2, q2, p1))
+	o4 := sign(e.area(p2, q2, q1))
 
 	if o1 != o2 && o3 != o4 {
 		return true // general case
@@ -755,13 +862,14 @@ func sign(num float64) int {
 }
 
 // check if a polygon diagonal intersects any polygon segments
-func intersectsPolygon(a, b *Node) bool {
+func (e *earcutter) intersectsPolygon(a, b *Node) bool {
 	p := a
 	for {
 		if p.i != a.i && p.next.i != a.i && p.i != b.i && p.next.i != b.i &&
-			intersects(p, p.next, a, b) {
+			e.intersects(p, p.next, a, b) {
 			return true
 		}
+		e.ticks++
 		p = p.next
 		if p == a {
 			break
@@ -771,24 +879,24 @@ func intersectsPolygon(a, b *Node) bool {
 }
 
 // check if a polygon diagonal is locally inside the polygon
-func locallyInside(a, b *Node) bool {
-	if area(a.prev, a, a.next) < 0 {
-		return area(a, b, a.next) >= 0 && area(a, a.prev, b) >= 0
+func (e *earcutter) locallyInside(a, b *Node) bool {
+	if e.area(a.prev, a, a.next) < 0 {
+		return e.area(a, b, a.next) >= 0 && e.area(a, a.prev, b) >= 0
 	}
-	return area(a, b, a.prev) < 0 || area(a, a.next, b) < 0
+	return e.area(a, b, a.prev) < 0 || e.area(a, a.next, b) < 0
 }
 
 // check if the middle point of a polygon diagonal is inside the polygon
-func middleInside(a, b *Node) bool {
+func (e *earcutter) middleInside(a, b *Node) bool {
 	p := a
 	inside := false
 	px := (a.x + b.x) / 2
 	py := (a.y + b.y) / 2
 	for {
-		if ((p.y > py) != (p.next.y > py)) && p.next.y != p.y &&
-			(px < (p.next.x-p.x)*(py-p.y)/(p.next.y-p.y)+p.x) {
+		if ((p.y > py) != (p.next.y > py)) && p.next.y != p.y && e.leftOfCrossing(p, p.next, px, py) {
 			inside = !inside
 		}
+		e.ticks++
 		p = p.next
 		if p == a {
 			break
@@ -797,11 +905,23 @@ func middleInside(a, b *Node) bool {
 	return inside
 }
 
+// whether (px, py) lies left of where the non-horizontal edge pq crosses its horizontal line
+func (e *earcutter) leftOfCrossing(p, q *Node, px, py float64) bool {
+	if e.opts.Robust {
+		// the crossing is right of the point when the point is left of the upward edge
+		if q.y > p.y {
+			return Orient2D(p.x, p.y, q.x, q.y, px, py) > 0
+		}
+		return Orient2D(p.x, p.y, q.x, q.y, px, py) < 0
+	}
+	return px < (q.x-p.x)*(py-p.y)/(q.y-p.y)+p.x
+}
+
 // link two polygon vertices with a bridge; if the vertices belong to the same ring, it splits polygon into two;
 // if one belongs to the outer ring and another to a hole, it merges it into a single ring
-func splitPolygon(a, b *Node) *Node {
-	a2 := createNode(a.i, a.x, a.y)
-	b2 := createNode(b.i, b.x, b.y)
+func (e *earcutter) splitPolygon(a, b *Node) *Node {
+	a2 := e.createNode(a.i, a.x, a.y)
+	b2 := e.createNode(b.i, b.x, b.y)
 	an := a.next
 	bp := b.prev
 
@@ -820,18 +940,12 @@ func splitPolygon(a, b *Node) *Node {
 	return b2
 }
 
-func createNode(i int, x, y float64) *Node {
-	return &Node{
-		i:       i,
-		x:       x,
-		y:       y,
-		prev:    nil,
-		next:    nil,
-		z:       0,
-		prevZ:   nil,
-		nextZ:   nil,
-		steiner: false,
-	}
+func (e *earcutter) createNode(i int, x, y float64) *Node {
+	p := e.nodes.get()
+	p.i = i
+	p.x = x
+	p.y = y
+	return p
 }
 
 // Deviation returns a percentage difference between the polygon area and its triangulation area;
@@ -875,9 +989,35 @@ func Deviation(data []float64, holeIndices []int, dim int, triangles []int) floa
 	return math.Abs((trianglesArea - polygonArea) / polygonArea)
 }
 
+// HoleBridges returns the cuts Earcut makes to join each hole to the outer ring before it
+// triangulates, in the order it makes them. Each is a pair of vertex indices: a vertex of the
+// outer ring or of a hole joined earlier, and the hole's leftmost vertex. It returns nil for
+// input EarcutE rejects.
+func HoleBridges(data []float64, holeIndices []int, dim int) [][2]int {
+	if dim == 0 {
+		dim = 2
+	}
+	if checkInput(data, holeIndices, dim) != nil {
+		return nil
+	}
+	e := &earcutter{traceBridges: true}
+	run(e, []int{}, data, holeIndices, dim)
+	return e.bridges
+}
+
+// Polygon holds the arguments Earcut takes for a single polygon.
+type Polygon struct {
+	// Data is the flat vertex array, Dim coordinates per vertex.
+	Data []float64
+	// Holes holds the index of the first vertex of each hole.
+	Holes []int
+	// Dim is the number of coordinates per vertex.
+	Dim int
+}
+
 // Flatten turns a polygon in a multi-dimensional array form (e.g. as in GeoJSON) into a form Earcut accepts
 func Flatten(data [][][]float64) (vertices []float64, holes []int, dim int) {
-	if len(data) == 0 {
+	if len(data) == 0 || len(data[0]) == 0 {
 		return nil, nil, 0
 	}
 
@@ -0,0 +1,67 @@
+package earcut
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Errors returned by EarcutE, EarcutT, EarcutContext and EarcutRingsE when the input cannot be
+// triangulated.
+var (
+	// ErrBadDimension is returned when dim is lower than 2.
+	ErrBadDimension = errors.New("earcut: dimension must be at least 2")
+	// ErrBadDataLength is returned when len(data) is not a multiple of dim.
+	ErrBadDataLength = errors.New("earcut: data length is not a multiple of dimension")
+	// ErrHoleIndexOutOfRange is returned when a hole index does not point inside data.
+	ErrHoleIndexOutOfRange = errors.New("earcut: hole index out of range")
+	// ErrUnsortedHoleIndices is returned when hole indices are not strictly increasing.
+	ErrUnsortedHoleIndices = errors.New("earcut: hole indices are not strictly increasing")
+	// ErrNonFiniteCoordinate is returned when a coordinate is NaN or infinite.
+	ErrNonFiniteCoordinate = errors.New("earcut: non-finite coordinate")
+	// ErrIndexOverflow is returned when a vertex index does not fit the requested index type.
+	ErrIndexOverflow = errors.New("earcut: vertex index overflows index type")
+	// ErrBudgetExceeded is returned by EarcutContext when Options.MaxWork runs out.
+	ErrBudgetExceeded = errors.New("earcut: work budget exceeded")
+)
+
+// EarcutE is like Earcut but validates the input first and returns an error
+// wrapping one of the Err* values instead of panicking or silently producing garbage.
+func EarcutE(data []float64, holeIndices []int, dim int) ([]int, error) {
+	if dim == 0 {
+		dim = 2
+	}
+	if err := checkInput(data, holeIndices, dim); err != nil {
+		return nil, err
+	}
+	return Earcut(data, holeIndices, dim), nil
+}
+
+// check that data, holeIndices and dim describe a polygon the triangulation can walk safely
+func checkInput[F Float](data []F, holeIndices []int, dim int) error {
+	if dim < 2 {
+		return fmt.Errorf("%w: got %d", ErrBadDimension, dim)
+	}
+	if len(data)%dim != 0 {
+		return fmt.Errorf("%w: %d values with dimension %d", ErrBadDataLength, len(data), dim)
+	}
+
+	n := len(data) / dim
+	for i, h := range holeIndices {
+		// a hole must start after the outer ring's first vertex and contain at least one vertex
+		if h <= 0 || h >= n {
+			return fmt.Errorf("%w: hole %d starts at vertex %d of %d", ErrHoleIndexOutOfRange, i, h, n)
+		}
+		if i > 0 && h <= holeIndices[i-1] {
+			return fmt.Errorf("%w: hole %d starts at vertex %d after %d", ErrUnsortedHoleIndices, i, h, holeIndices[i-1])
+		}
+	}
+
+	for i, v := range data {
+		if f := float64(v); math.IsNaN(f) || math.IsInf(f, 0) {
+			return fmt.Errorf("%w: vertex %d coordinate %d is %v", ErrNonFiniteCoordinate, i/dim, i%dim, v)
+		}
+	}
+
+	return nil
+}
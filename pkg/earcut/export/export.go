@@ -0,0 +1,178 @@
+// Package export writes triangulations as meshes that 3D tools such as Blender and MeshLab
+// open: Wavefront OBJ, ASCII and binary STL, ASCII PLY and binary glTF.
+package export
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// ErrInvalidMesh is returned when a Mesh doesn't describe triangles over its vertices.
+var ErrInvalidMesh = errors.New("export: invalid mesh")
+
+// Mesh is a triangulation to write.
+type Mesh struct {
+	// Vertices holds Dim coordinates per vertex. The first three are written as x, y and z,
+	// with z = 0 when Dim is 2.
+	Vertices []float64
+	// Dim is the number of coordinates per vertex, at least 2.
+	Dim int
+	// Triangles holds three vertex indices per triangle, as returned by earcut.Earcut.
+	Triangles []int
+	// Normals adds a normal to every face in OBJ and PLY output. STL always has them.
+	Normals bool
+}
+
+// check that m can be written
+func (m *Mesh) check() error {
+	if m.Dim < 2 {
+		return fmt.Errorf("%w: dimension %d", ErrInvalidMesh, m.Dim)
+	}
+	if len(m.Vertices)%m.Dim != 0 {
+		return fmt.Errorf("%w: %d values with dimension %d", ErrInvalidMesh, len(m.Vertices), m.Dim)
+	}
+	if len(m.Triangles)%3 != 0 {
+		return fmt.Errorf("%w: %d indices is not a whole number of triangles", ErrInvalidMesh, len(m.Triangles))
+	}
+	n := len(m.Vertices) / m.Dim
+	for _, i := range m.Triangles {
+		if i < 0 || i >= n {
+			return fmt.Errorf("%w: index %d out of range for %d vertices", ErrInvalidMesh, i, n)
+		}
+	}
+	return nil
+}
+
+// the position of vertex i in 3D
+func (m *Mesh) position(i int) [3]float64 {
+	v := m.Vertices[i*m.Dim:]
+	if m.Dim == 2 {
+		return [3]float64{v[0], v[1], 0}
+	}
+	return [3]float64{v[0], v[1], v[2]}
+}
+
+// the unit normal of triangle t, following the right-hand rule, or zero when it is degenerate
+func (m *Mesh) normal(t int) [3]float64 {
+	a, b, c := m.position(m.Triangles[3*t]), m.position(m.Triangles[3*t+1]), m.position(m.Triangles[3*t+2])
+	u := [3]float64{b[0] - a[0], b[1] - a[1], b[2] - a[2]}
+	v := [3]float64{c[0] - a[0], c[1] - a[1], c[2] - a[2]}
+	n := [3]float64{u[1]*v[2] - u[2]*v[1], u[2]*v[0] - u[0]*v[2], u[0]*v[1] - u[1]*v[0]}
+	length := math.Sqrt(n[0]*n[0] + n[1]*n[1] + n[2]*n[2])
+	if length == 0 {
+		return [3]float64{}
+	}
+	return [3]float64{n[0] / length, n[1] / length, n[2] / length}
+}
+
+// WriteOBJ writes m as a Wavefront OBJ file with 1-based faces, preceded by one vn line per
+// face when m.Normals is set.
+func WriteOBJ(w io.Writer, m Mesh) error {
+	if err := m.check(); err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(w)
+	for i := 0; i < len(m.Vertices)/m.Dim; i++ {
+		p := m.position(i)
+		fmt.Fprintf(bw, "v %g %g %g\n", p[0], p[1], p[2])
+	}
+	for t := 0; t < len(m.Triangles)/3; t++ {
+		a, b, c := m.Triangles[3*t]+1, m.Triangles[3*t+1]+1, m.Triangles[3*t+2]+1
+		if m.Normals {
+			n := m.normal(t)
+			fmt.Fprintf(bw, "vn %g %g %g\nf %d//%d %d//%d %d//%d\n", n[0], n[1], n[2], a, t+1, b, t+1, c, t+1)
+		} else {
+			fmt.Fprintf(bw, "f %d %d %d\n", a, b, c)
+		}
+	}
+	return bw.Flush()
+}
+
+// WriteSTL writes m as an ASCII STL solid with the given name.
+func WriteSTL(w io.Writer, m Mesh, name string) error {
+	if err := m.check(); err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "solid %s\n", name)
+	for t := 0; t < len(m.Triangles)/3; t++ {
+		n := m.normal(t)
+		fmt.Fprintf(bw, "facet normal %g %g %g\nouter loop\n", n[0], n[1], n[2])
+		for k := 0; k < 3; k++ {
+			p := m.position(m.Triangles[3*t+k])
+			fmt.Fprintf(bw, "vertex %g %g %g\n", p[0], p[1], p[2])
+		}
+		fmt.Fprint(bw, "endloop\nendfacet\n")
+	}
+	fmt.Fprintf(bw, "endsolid %s\n", name)
+	return bw.Flush()
+}
+
+// WriteBinarySTL writes m as a binary STL file, which stores coordinates as float32.
+func WriteBinarySTL(w io.Writer, m Mesh) error {
+	if err := m.check(); err != nil {
+		return err
+	}
+	n := len(m.Triangles) / 3
+	if uint64(n) > math.MaxUint32 {
+		return fmt.Errorf("%w: %d triangles don't fit binary STL", ErrInvalidMesh, n)
+	}
+
+	bw := bufio.NewWriter(w)
+	var header [80]byte
+	copy(header[:], "earcut-go")
+	bw.Write(header[:])
+	bw.Write(binary.LittleEndian.AppendUint32(nil, uint32(n)))
+
+	facet := make([]byte, 0, 50)
+	for t := 0; t < n; t++ {
+		facet = facet[:0]
+		normal := m.normal(t)
+		for _, v := range normal {
+			facet = binary.LittleEndian.AppendUint32(facet, math.Float32bits(float32(v)))
+		}
+		for k := 0; k < 3; k++ {
+			for _, v := range m.position(m.Triangles[3*t+k]) {
+				facet = binary.LittleEndian.AppendUint32(facet, math.Float32bits(float32(v)))
+			}
+		}
+		// attribute byte count, unused
+		facet = append(facet, 0, 0)
+		bw.Write(facet)
+	}
+	return bw.Flush()
+}
+
+// WritePLY writes m as an ASCII PLY file, with nx, ny and nz face properties when m.Normals
+// is set.
+func WritePLY(w io.Writer, m Mesh) error {
+	if err := m.check(); err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "ply\nformat ascii 1.0\ncomment earcut-go\n")
+	fmt.Fprintf(bw, "element vertex %d\nproperty double x\nproperty double y\nproperty double z\n", len(m.Vertices)/m.Dim)
+	fmt.Fprintf(bw, "element face %d\nproperty list uchar int vertex_indices\n", len(m.Triangles)/3)
+	if m.Normals {
+		fmt.Fprint(bw, "property float nx\nproperty float ny\nproperty float nz\n")
+	}
+	fmt.Fprint(bw, "end_header\n")
+
+	for i := 0; i < len(m.Vertices)/m.Dim; i++ {
+		p := m.position(i)
+		fmt.Fprintf(bw, "%g %g %g\n", p[0], p[1], p[2])
+	}
+	for t := 0; t < len(m.Triangles)/3; t++ {
+		fmt.Fprintf(bw, "3 %d %d %d", m.Triangles[3*t], m.Triangles[3*t+1], m.Triangles[3*t+2])
+		if m.Normals {
+			n := m.normal(t)
+			fmt.Fprintf(bw, " %g %g %g", n[0], n[1], n[2])
+		}
+		fmt.Fprint(bw, "\n")
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,215 @@
+package export
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+)
+
+// glTF constants
+const (
+	glbMagic     = 0x46546C67 // "glTF"
+	glbJSONChunk = 0x4E4F534A // "JSON"
+	glbBINChunk  = 0x004E4942 // "BIN\x00"
+
+	gltfFloat         = 5126
+	gltfUnsignedByte  = 5121
+	gltfUnsignedShort = 5123
+	gltfUnsignedInt   = 5125
+
+	gltfArrayBuffer        = 34962
+	gltfElementArrayBuffer = 34963
+	gltfTriangles          = 4
+)
+
+// the parts of a glTF document WriteGLB fills in
+type gltfDocument struct {
+	Asset       gltfAsset        `json:"asset"`
+	Scene       int              `json:"scene"`
+	Scenes      []gltfScene      `json:"scenes"`
+	Nodes       []gltfNode       `json:"nodes"`
+	Meshes      []gltfMesh       `json:"meshes,omitempty"`
+	Accessors   []gltfAccessor   `json:"accessors,omitempty"`
+	BufferViews []gltfBufferView `json:"bufferViews,omitempty"`
+	Buffers     []gltfBuffer     `json:"buffers,omitempty"`
+}
+
+type gltfAsset struct {
+	Version   string `json:"version"`
+	Generator string `json:"generator"`
+}
+
+type gltfScene struct {
+	Nodes []int `json:"nodes"`
+}
+
+type gltfNode struct {
+	Name        string      `json:"name,omitempty"`
+	Mesh        *int        `json:"mesh,omitempty"`
+	Translation *[3]float64 `json:"translation,omitempty"`
+}
+
+type gltfMesh struct {
+	Name       string          `json:"name,omitempty"`
+	Primitives []gltfPrimitive `json:"primitives"`
+}
+
+type gltfPrimitive struct {
+	Attributes map[string]int `json:"attributes"`
+	Indices    int            `json:"indices"`
+	Mode       int            `json:"mode"`
+}
+
+type gltfAccessor struct {
+	BufferView    int       `json:"bufferView"`
+	ComponentType int       `json:"componentType"`
+	Count         int       `json:"count"`
+	Type          string    `json:"type"`
+	Min           []float64 `json:"min,omitempty"`
+	Max           []float64 `json:"max,omitempty"`
+}
+
+type gltfBufferView struct {
+	Buffer     int `json:"buffer"`
+	ByteOffset int `json:"byteOffset"`
+	ByteLength int `json:"byteLength"`
+	Target     int `json:"target"`
+}
+
+type gltfBuffer struct {
+	ByteLength int `json:"byteLength"`
+}
+
+// WriteGLB writes meshes as a binary glTF 2.0 file with one node and mesh per input mesh, named
+// after names[i] when there is one, such as a feature property. glTF has y pointing up, so a
+// vertex (x, y, z) becomes (x, z, -y) and flat polygons face up. Positions are stored as
+// float32 relative to the centre of each mesh, which becomes the node's translation, so map
+// coordinates keep their precision. Indices use the smallest unsigned type that holds them.
+// Meshes without triangles become nodes without a mesh.
+func WriteGLB(w io.Writer, meshes []Mesh, names []string) error {
+	doc := gltfDocument{
+		Asset:  gltfAsset{Version: "2.0", Generator: "earcut-go"},
+		Scenes: []gltfScene{{Nodes: []int{}}},
+	}
+	var bin []byte
+
+	for k, m := range meshes {
+		if err := m.check(); err != nil {
+			return fmt.Errorf("mesh %d: %w", k, err)
+		}
+		node := gltfNode{}
+		if k < len(names) {
+			node.Name = names[k]
+		}
+		doc.Scenes[0].Nodes = append(doc.Scenes[0].Nodes, len(doc.Nodes))
+		if len(m.Triangles) == 0 {
+			doc.Nodes = append(doc.Nodes, node)
+			continue
+		}
+
+		// positions in glTF axes, relative to the centre of their bounds
+		n := len(m.Vertices) / m.Dim
+		lo := [3]float64{math.Inf(1), math.Inf(1), math.Inf(1)}
+		hi := [3]float64{math.Inf(-1), math.Inf(-1), math.Inf(-1)}
+		positions := make([][3]float64, n)
+		for i := range positions {
+			p := m.position(i)
+			positions[i] = [3]float64{p[0], p[2], -p[1]}
+			for c := 0; c < 3; c++ {
+				lo[c], hi[c] = math.Min(lo[c], positions[i][c]), math.Max(hi[c], positions[i][c])
+			}
+		}
+		center := [3]float64{(lo[0] + hi[0]) / 2, (lo[1] + hi[1]) / 2, (lo[2] + hi[2]) / 2}
+		node.Translation = &center
+
+		posMin := []float64{math.Inf(1), math.Inf(1), math.Inf(1)}
+		posMax := []float64{math.Inf(-1), math.Inf(-1), math.Inf(-1)}
+		start := len(bin)
+		for _, p := range positions {
+			for c := 0; c < 3; c++ {
+				// the bounds must match the stored float32 values exactly
+				v := float32(p[c] - center[c])
+				posMin[c], posMax[c] = math.Min(posMin[c], float64(v)), math.Max(posMax[c], float64(v))
+				bin = binary.LittleEndian.AppendUint32(bin, math.Float32bits(v))
+			}
+		}
+		positionAccessor := len(doc.Accessors)
+		doc.BufferViews = append(doc.BufferViews, gltfBufferView{ByteOffset: start, ByteLength: len(bin) - start, Target: gltfArrayBuffer})
+		doc.Accessors = append(doc.Accessors, gltfAccessor{BufferView: len(doc.BufferViews) - 1, ComponentType: gltfFloat,
+			Count: n, Type: "VEC3", Min: posMin, Max: posMax})
+
+		// glTF reserves the largest value of each index type for primitive restart
+		componentType := gltfUnsignedInt
+		switch {
+		case n <= math.MaxUint8:
+			componentType = gltfUnsignedByte
+		case n <= math.MaxUint16:
+			componentType = gltfUnsignedShort
+		case uint64(n) > math.MaxUint32:
+			return fmt.Errorf("mesh %d: %w: %d vertices don't fit 32-bit indices", k, ErrInvalidMesh, n)
+		}
+		start = len(bin)
+		for _, i := range m.Triangles {
+			switch componentType {
+			case gltfUnsignedByte:
+				bin = append(bin, byte(i))
+			case gltfUnsignedShort:
+				bin = binary.LittleEndian.AppendUint16(bin, uint16(i))
+			default:
+				bin = binary.LittleEndian.AppendUint32(bin, uint32(i))
+			}
+		}
+		indexAccessor := len(doc.Accessors)
+		doc.BufferViews = append(doc.BufferViews, gltfBufferView{ByteOffset: start, ByteLength: len(bin) - start, Target: gltfElementArrayBuffer})
+		doc.Accessors = append(doc.Accessors, gltfAccessor{BufferView: len(doc.BufferViews) - 1, ComponentType: componentType,
+			Count: len(m.Triangles), Type: "SCALAR"})
+		// keep the next float32 view aligned
+		for len(bin)%4 != 0 {
+			bin = append(bin, 0)
+		}
+
+		mesh := len(doc.Meshes)
+		doc.Meshes = append(doc.Meshes, gltfMesh{Name: node.Name, Primitives: []gltfPrimitive{{
+			Attributes: map[string]int{"POSITION": positionAccessor},
+			Indices:    indexAccessor,
+			Mode:       gltfTriangles,
+		}}})
+		node.Mesh = &mesh
+		doc.Nodes = append(doc.Nodes, node)
+	}
+	if len(bin) > 0 {
+		doc.Buffers = []gltfBuffer{{ByteLength: len(bin)}}
+	}
+
+	js, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	// chunks are padded to 4 bytes, JSON with spaces
+	js = append(js, bytes.Repeat([]byte(" "), (4-len(js)%4)%4)...)
+
+	length := 12 + 8 + len(js)
+	if len(bin) > 0 {
+		length += 8 + len(bin)
+	}
+	if uint64(length) > math.MaxUint32 {
+		return fmt.Errorf("%w: %d bytes don't fit a GLB file", ErrInvalidMesh, length)
+	}
+	out := make([]byte, 0, length)
+	out = binary.LittleEndian.AppendUint32(out, glbMagic)
+	out = binary.LittleEndian.AppendUint32(out, 2)
+	out = binary.LittleEndian.AppendUint32(out, uint32(length))
+	out = binary.LittleEndian.AppendUint32(out, uint32(len(js)))
+	out = binary.LittleEndian.AppendUint32(out, glbJSONChunk)
+	out = append(out, js...)
+	if len(bin) > 0 {
+		out = binary.LittleEndian.AppendUint32(out, uint32(len(bin)))
+		out = binary.LittleEndian.AppendUint32(out, glbBINChunk)
+		out = append(out, bin...)
+	}
+	_, err = w.Write(out)
+	return err
+}
@@ -0,0 +1,280 @@
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"earcut-go/pkg/earcut"
+)
+
+// SVGOptions configures WriteSVG.
+type SVGOptions struct {
+	// Size is the longer side of the drawing in pixels; 0 means 800.
+	Size float64
+	// Labels writes the index of every vertex next to it.
+	Labels bool
+	// Bridges draws the cuts Earcut makes to join the holes to the outer ring, as found by
+	// earcut.HoleBridges.
+	Bridges bool
+}
+
+// colours of the triangles, picked in turn
+var svgPalette = []string{"#a6cee3", "#b2df8a", "#fdbf6f", "#cab2d6", "#ffff99", "#8dd3c7"}
+
+// the colour of the areas that make up the deviation
+const svgError = "#e31a1c"
+
+// WriteSVG draws polygons and their triangulation for debugging. The triangles index the
+// vertices of all the polygons numbered one after the other, as when their Data is joined.
+// Triangles are filled in turn with light colours over the polygons filled in red, so red shows
+// where the triangulation leaves part of a polygon out; triangles that stick out of their
+// polygon or overlap another one are filled red too. Together these are the areas
+// earcut.Deviation measures. Rings are outlined in black and hole bridges dashed.
+func WriteSVG(w io.Writer, polygons []earcut.Polygon, triangles []int, opts SVGOptions) error {
+	var xs, ys []float64
+	// the first vertex of each polygon and the vertex ranges of its rings
+	first := make([]int, 0, len(polygons)+1)
+	rings := make([][][2]int, len(polygons))
+	for k, p := range polygons {
+		dim := p.Dim
+		if dim == 0 {
+			dim = 2
+		}
+		if dim < 2 || len(p.Data)%dim != 0 {
+			return fmt.Errorf("polygon %d: %w: %d values with dimension %d", k, ErrInvalidMesh, len(p.Data), dim)
+		}
+		n := len(p.Data) / dim
+		first = append(first, len(xs))
+		starts := append([]int{0}, p.Holes...)
+		if n == 0 && len(p.Holes) == 0 {
+			// an empty polygon has no rings to draw
+			starts = nil
+		}
+		for r, start := range starts {
+			end := n
+			if r+1 < len(starts) {
+				end = starts[r+1]
+			}
+			if start < 0 || start >= end || end > n {
+				return fmt.Errorf("polygon %d: %w: ring %d is empty or out of range for %d vertices", k, ErrInvalidMesh, r, n)
+			}
+			rings[k] = append(rings[k], [2]int{len(xs) + start, len(xs) + end})
+		}
+		for i := 0; i < len(p.Data); i += dim {
+			xs, ys = append(xs, p.Data[i]), append(ys, p.Data[i+1])
+		}
+	}
+	first = append(first, len(xs))
+	if len(triangles)%3 != 0 {
+		return fmt.Errorf("%w: %d indices is not a whole number of triangles", ErrInvalidMesh, len(triangles))
+	}
+	for _, i := range triangles {
+		if i < 0 || i >= len(xs) {
+			return fmt.Errorf("%w: index %d out of range for %d vertices", ErrInvalidMesh, i, len(xs))
+		}
+	}
+
+	// fit the drawing into Size pixels with a margin, y pointing up
+	minX, minY, maxX, maxY := 0.0, 0.0, 1.0, 1.0
+	if len(xs) > 0 {
+		minX, minY, maxX, maxY = math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)
+		for i := range xs {
+			minX, maxX = math.Min(minX, xs[i]), math.Max(maxX, xs[i])
+			minY, maxY = math.Min(minY, ys[i]), math.Max(maxY, ys[i])
+		}
+	}
+	size := opts.Size
+	if size <= 0 {
+		size = 800
+	}
+	const margin = 20
+	scale := (size - 2*margin) / math.Max(math.Max(maxX-minX, maxY-minY), 1e-300)
+	px := func(i int) string { return svgNumber((xs[i] - minX) * scale) }
+	py := func(i int) string { return svgNumber((maxY - ys[i]) * scale) }
+	point := func(i int) string { return px(i) + "," + py(i) }
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%s\" height=\"%s\" viewBox=\"%s %s %[1]s %[2]s\">\n",
+		svgNumber((maxX-minX)*scale+2*margin), svgNumber((maxY-minY)*scale+2*margin), svgNumber(-margin), svgNumber(-margin))
+
+	// polygons under the triangles, showing through wherever they aren't covered
+	fmt.Fprintf(bw, "<g fill=\"%s\" fill-rule=\"evenodd\">\n", svgError)
+	for k := range polygons {
+		var d strings.Builder
+		for _, r := range rings[k] {
+			for i := r[0]; i < r[1]; i++ {
+				if i == r[0] {
+					d.WriteString("M")
+				} else {
+					d.WriteString(" L")
+				}
+				d.WriteString(point(i))
+			}
+			if r[1] > r[0] {
+				d.WriteString(" Z ")
+			}
+		}
+		fmt.Fprintf(bw, "<path d=\"%s\"/>\n", strings.TrimSpace(d.String()))
+	}
+	fmt.Fprintln(bw, "</g>")
+
+	bad := misplacedTriangles(xs, ys, first, rings, triangles)
+	fmt.Fprintln(bw, "<g stroke=\"#fff\" stroke-width=\"0.5\" stroke-linejoin=\"round\">")
+	// the misplaced triangles go last so the others don't hide them
+	for _, misplaced := range []bool{false, true} {
+		for t := 0; t < len(triangles)/3; t++ {
+			if bad[t] != misplaced {
+				continue
+			}
+			fill := svgPalette[t%len(svgPalette)]
+			if misplaced {
+				fill = svgError
+			}
+			fmt.Fprintf(bw, "<polygon points=\"%s %s %s\" fill=\"%s\"/>\n",
+				point(triangles[3*t]), point(triangles[3*t+1]), point(triangles[3*t+2]), fill)
+		}
+	}
+	fmt.Fprintln(bw, "</g>")
+
+	fmt.Fprintln(bw, "<g fill=\"none\" stroke=\"#000\" stroke-linejoin=\"round\">")
+	for k := range polygons {
+		for _, r := range rings[k] {
+			points := make([]string, 0, r[1]-r[0])
+			for i := r[0]; i < r[1]; i++ {
+				points = append(points, point(i))
+			}
+			fmt.Fprintf(bw, "<polygon points=\"%s\"/>\n", strings.Join(points, " "))
+		}
+	}
+	fmt.Fprintln(bw, "</g>")
+
+	if opts.Bridges {
+		fmt.Fprintln(bw, "<g stroke=\"#ff7f00\" stroke-width=\"1.5\" stroke-dasharray=\"4 3\">")
+		for k, p := range polygons {
+			for _, b := range earcut.HoleBridges(p.Data, p.Holes, p.Dim) {
+				a, h := first[k]+b[0], first[k]+b[1]
+				fmt.Fprintf(bw, "<line x1=\"%s\" y1=\"%s\" x2=\"%s\" y2=\"%s\"/>\n", px(a), py(a), px(h), py(h))
+			}
+		}
+		fmt.Fprintln(bw, "</g>")
+	}
+
+	if opts.Labels {
+		fmt.Fprintln(bw, "<g font-family=\"sans-serif\" font-size=\"10\">")
+		for i := range xs {
+			fmt.Fprintf(bw, "<circle cx=\"%s\" cy=\"%s\" r=\"2\"/><text x=\"%s\" y=\"%s\">%d</text>\n",
+				px(i), py(i), svgNumber((xs[i]-minX)*scale+3), svgNumber((maxY-ys[i])*scale-3), i)
+		}
+		fmt.Fprintln(bw, "</g>")
+	}
+
+	fmt.Fprintln(bw, "</svg>")
+	return bw.Flush()
+}
+
+// a pixel coordinate rounded to hundredths
+func svgNumber(v float64) string {
+	return strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64)
+}
+
+// which triangles have their centre outside the polygon of their first vertex or overlap another
+// triangle; flat triangles cover nothing and are never misplaced
+func misplacedTriangles(xs, ys []float64, first []int, rings [][][2]int, triangles []int) []bool {
+	n := len(triangles) / 3
+	corners := make([][3][2]float64, n)
+	bad := make([]bool, n)
+	for t := range corners {
+		for k := 0; k < 3; k++ {
+			corners[t][k] = [2]float64{xs[triangles[3*t+k]], ys[triangles[3*t+k]]}
+		}
+		if orient(corners[t][0], corners[t][1], corners[t][2]) == 0 {
+			continue
+		}
+		x, y := centre(corners[t])
+		polygon := sort.SearchInts(first, triangles[3*t]+1) - 1
+		inside := false
+		for _, r := range rings[polygon] {
+			if pointInRing(xs[r[0]:r[1]], ys[r[0]:r[1]], x, y) {
+				inside = !inside
+			}
+		}
+		bad[t] = !inside
+	}
+
+	// sweep the triangles from left to right, comparing those whose boxes overlap
+	boxes := make([][4]float64, n)
+	order := make([]int, n)
+	for t, c := range corners {
+		boxes[t] = [4]float64{
+			math.Min(c[0][0], math.Min(c[1][0], c[2][0])), math.Min(c[0][1], math.Min(c[1][1], c[2][1])),
+			math.Max(c[0][0], math.Max(c[1][0], c[2][0])), math.Max(c[0][1], math.Max(c[1][1], c[2][1])),
+		}
+		order[t] = t
+	}
+	sort.Slice(order, func(i, j int) bool { return boxes[order[i]][0] < boxes[order[j]][0] })
+	var active []int
+	for _, t := range order {
+		kept := active[:0]
+		for _, u := range active {
+			if boxes[u][2] <= boxes[t][0] {
+				continue
+			}
+			kept = append(kept, u)
+			if boxes[u][1] < boxes[t][3] && boxes[t][1] < boxes[u][3] && trianglesOverlap(corners[t], corners[u]) {
+				bad[t], bad[u] = true, true
+			}
+		}
+		active = append(kept, t)
+	}
+	return bad
+}
+
+func orient(a, b, c [2]float64) float64 {
+	return (b[0]-a[0])*(c[1]-a[1]) - (b[1]-a[1])*(c[0]-a[0])
+}
+
+func centre(t [3][2]float64) (x, y float64) {
+	return (t[0][0] + t[1][0] + t[2][0]) / 3, (t[0][1] + t[1][1] + t[2][1]) / 3
+}
+
+// whether the insides of two triangles overlap
+func trianglesOverlap(p, q [3][2]float64) bool {
+	if orient(p[0], p[1], p[2]) == 0 || orient(q[0], q[1], q[2]) == 0 {
+		return false
+	}
+	// edges crossing each other
+	for i := 0; i < 3; i++ {
+		a, b := p[i], p[(i+1)%3]
+		for j := 0; j < 3; j++ {
+			c, d := q[j], q[(j+1)%3]
+			if orient(a, b, c)*orient(a, b, d) < 0 && orient(c, d, a)*orient(c, d, b) < 0 {
+				return true
+			}
+		}
+	}
+	// one triangle inside the other, found by its centre
+	inside := func(t [3][2]float64, x, y float64) bool {
+		c := [2]float64{x, y}
+		s0, s1, s2 := orient(t[0], t[1], c), orient(t[1], t[2], c), orient(t[2], t[0], c)
+		return s0 > 0 && s1 > 0 && s2 > 0 || s0 < 0 && s1 < 0 && s2 < 0
+	}
+	px, py := centre(p)
+	qx, qy := centre(q)
+	return inside(q, px, py) || inside(p, qx, qy)
+}
+
+// whether the point lies inside the ring, by the crossings of a ray to the right
+func pointInRing(xs, ys []float64, x, y float64) bool {
+	inside := false
+	for i, j := 0, len(xs)-1; i < len(xs); j, i = i, i+1 {
+		if (ys[i] > y) != (ys[j] > y) && x < (xs[j]-xs[i])*(y-ys[i])/(ys[j]-ys[i])+xs[i] {
+			inside = !inside
+		}
+	}
+	return inside
+}
@@ -0,0 +1,59 @@
+package earcut
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Float is the set of coordinate types EarcutT accepts.
+type Float interface {
+	~float32 | ~float64
+}
+
+// Index is the set of index types EarcutT can produce, matching common GPU index buffers.
+type Index interface {
+	~uint16 | ~uint32 | ~int
+}
+
+// EarcutT is like EarcutE but reads coordinates of any Float type and returns indices of any
+// Index type, so vertex and index buffers can be used without conversion copies.
+// It returns an error wrapping ErrIndexOverflow if the polygon has more vertices than I can address.
+func EarcutT[F Float, I Index](data []F, holeIndices []int, dim int) ([]I, error) {
+	var t Triangulator
+	indices, err := TriangulateT(&t, []I{}, data, holeIndices, dim)
+	if err != nil {
+		return nil, err
+	}
+	return indices, nil
+}
+
+// TriangulateT is the generic counterpart of Triangulator.Triangulate: it validates the input like
+// EarcutT and appends the triangle indices to dst, reusing t's buffers between calls. When
+// t.Options.MaxWork runs out it returns dst with the triangles found so far and an error
+// wrapping ErrBudgetExceeded, like EarcutContext.
+func TriangulateT[F Float, I Index](t *Triangulator, dst []I, data []F, holeIndices []int, dim int) ([]I, error) {
+	if dim == 0 {
+		dim = 2
+	}
+	if err := checkInput(data, holeIndices, dim); err != nil {
+		return dst, err
+	}
+	if n := len(data) / dim; n > 0 && !fitsIndex[I](n-1) {
+		var zero I
+		return dst, fmt.Errorf("%w: %d vertices do not fit %T", ErrIndexOverflow, n, zero)
+	}
+
+	t.e.opts = t.Options
+	t.scratch = run(&t.e, t.scratch[:0], data, holeIndices, dim)
+
+	dst = slices.Grow(dst, len(t.scratch))
+	for _, i := range t.scratch {
+		dst = append(dst, I(i))
+	}
+	return dst, t.e.err
+}
+
+// whether i survives a round trip through I
+func fitsIndex[I Index](i int) bool {
+	return i >= 0 && int(I(i)) == i
+}
@@ -0,0 +1,251 @@
+// Package geojson triangulates the Polygon and MultiPolygon geometries of GeoJSON documents
+// (RFC 7946) with earcut, combining them into a single mesh.
+package geojson
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+
+	"earcut-go/pkg/earcut"
+)
+
+// Errors returned when a document can't be triangulated.
+var (
+	// ErrUnknownType is returned for a "type" member that isn't a GeoJSON object type.
+	ErrUnknownType = errors.New("geojson: unknown object type")
+	// ErrBadPosition is returned for a position with fewer than two coordinates.
+	ErrBadPosition = errors.New("geojson: position needs at least two coordinates")
+	// ErrMixedDimensions is returned when positions don't all have the same number of coordinates.
+	ErrMixedDimensions = errors.New("geojson: positions have different dimensions")
+)
+
+// Feature describes the origin of a group of triangles in a Mesh.
+type Feature struct {
+	// ID is the feature's "id" member, nil when absent or for bare geometries.
+	ID any
+	// Properties is the feature's "properties" member.
+	Properties map[string]any
+}
+
+// Mesh is the combined triangulation of every polygon in a document.
+type Mesh struct {
+	// Vertices holds Dim coordinates per vertex, without the closing duplicate of each ring.
+	Vertices []float64
+	// Dim is the number of coordinates per position, 2 or more.
+	Dim int
+	// Indices holds three vertex indices per triangle.
+	Indices []int
+	// TriangleFeatures holds, for each triangle, its index into Features.
+	TriangleFeatures []int
+	// Features lists the features in document order; a bare geometry counts as one feature.
+	Features []Feature
+}
+
+// Decode reads a GeoJSON Polygon, MultiPolygon, GeometryCollection, Feature or FeatureCollection
+// from r and triangulates it. Other geometry types are skipped since they have no area.
+func Decode(r io.Reader) (*Mesh, error) {
+	var obj object
+	if err := json.NewDecoder(r).Decode(&obj); err != nil {
+		return nil, fmt.Errorf("geojson: %w", err)
+	}
+	return triangulate(&obj)
+}
+
+// Unmarshal is like Decode for a document already in memory.
+func Unmarshal(data []byte) (*Mesh, error) {
+	var obj object
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, fmt.Errorf("geojson: %w", err)
+	}
+	return triangulate(&obj)
+}
+
+// DecodePolygons reads a document like Decode but returns its polygons, with the closing
+// duplicate of each ring dropped, instead of triangulating them. names holds for each polygon
+// the "name" property of the feature it comes from if that is a non-empty string, or else the
+// feature's id, or else "".
+func DecodePolygons(r io.Reader) (polygons []earcut.Polygon, names []string, err error) {
+	var obj object
+	if err := json.NewDecoder(r).Decode(&obj); err != nil {
+		return nil, nil, fmt.Errorf("geojson: %w", err)
+	}
+	b := &builder{}
+	if err := b.addObject(&obj); err != nil {
+		return nil, nil, err
+	}
+	names = make([]string, len(b.polygons))
+	for k, f := range b.polygonFeatures {
+		names[k] = featureName(b.mesh.Features[f])
+	}
+	return b.polygons, names, nil
+}
+
+// the "name" property of a feature, or its id
+func featureName(f Feature) string {
+	if name, ok := f.Properties["name"].(string); ok && name != "" {
+		return name
+	}
+	if f.ID != nil {
+		return fmt.Sprint(f.ID)
+	}
+	return ""
+}
+
+// object has the members of every GeoJSON object type we read
+type object struct {
+	Type        string            `json:"type"`
+	Coordinates json.RawMessage   `json:"coordinates"`
+	Geometries  []json.RawMessage `json:"geometries"`
+	Geometry    json.RawMessage   `json:"geometry"`
+	Features    []json.RawMessage `json:"features"`
+	ID          any               `json:"id"`
+	Properties  map[string]any    `json:"properties"`
+}
+
+// builder collects the polygons and features of a document
+type builder struct {
+	mesh     Mesh
+	polygons []earcut.Polygon
+	// index into mesh.Features of each polygon and of the feature being read
+	polygonFeatures []int
+	feature         int
+}
+
+func triangulate(obj *object) (*Mesh, error) {
+	b := &builder{}
+	if err := b.addObject(obj); err != nil {
+		return nil, err
+	}
+
+	var tr earcut.Triangulator
+	for k, p := range b.polygons {
+		offset := len(b.mesh.Vertices) / p.Dim
+		b.mesh.Vertices = append(b.mesh.Vertices, p.Data...)
+
+		start := len(b.mesh.Indices)
+		b.mesh.Indices = tr.Triangulate(b.mesh.Indices, p.Data, p.Holes, p.Dim)
+		for i := start; i < len(b.mesh.Indices); i++ {
+			b.mesh.Indices[i] += offset
+		}
+		for i := start; i < len(b.mesh.Indices); i += 3 {
+			b.mesh.TriangleFeatures = append(b.mesh.TriangleFeatures, b.polygonFeatures[k])
+		}
+	}
+	return &b.mesh, nil
+}
+
+func (b *builder) addObject(obj *object) error {
+	switch obj.Type {
+	case "FeatureCollection":
+		for _, raw := range obj.Features {
+			var f object
+			if err := json.Unmarshal(raw, &f); err != nil {
+				return fmt.Errorf("geojson: %w", err)
+			}
+			if f.Type != "Feature" {
+				return fmt.Errorf("%w: %q in FeatureCollection", ErrUnknownType, f.Type)
+			}
+			if err := b.addObject(&f); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "Feature":
+		b.feature = len(b.mesh.Features)
+		b.mesh.Features = append(b.mesh.Features, Feature{ID: obj.ID, Properties: obj.Properties})
+		if len(obj.Geometry) == 0 || string(obj.Geometry) == "null" {
+			return nil
+		}
+		var g object
+		if err := json.Unmarshal(obj.Geometry, &g); err != nil {
+			return fmt.Errorf("geojson: %w", err)
+		}
+		return b.addGeometry(&g)
+	default:
+		b.feature = len(b.mesh.Features)
+		b.mesh.Features = append(b.mesh.Features, Feature{})
+		return b.addGeometry(obj)
+	}
+}
+
+func (b *builder) addGeometry(g *object) error {
+	switch g.Type {
+	case "Polygon":
+		var rings [][][]float64
+		if err := json.Unmarshal(g.Coordinates, &rings); err != nil {
+			return fmt.Errorf("geojson: Polygon coordinates: %w", err)
+		}
+		return b.addPolygon(rings)
+	case "MultiPolygon":
+		var polygons [][][][]float64
+		if err := json.Unmarshal(g.Coordinates, &polygons); err != nil {
+			return fmt.Errorf("geojson: MultiPolygon coordinates: %w", err)
+		}
+		for _, rings := range polygons {
+			if err := b.addPolygon(rings); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "GeometryCollection":
+		for _, raw := range g.Geometries {
+			var child object
+			if err := json.Unmarshal(raw, &child); err != nil {
+				return fmt.Errorf("geojson: %w", err)
+			}
+			if err := b.addGeometry(&child); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "Point", "MultiPoint", "LineString", "MultiLineString":
+		return nil
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownType, g.Type)
+	}
+}
+
+// add a polygon, leaving it out when its exterior ring is empty rather than taking its first
+// hole for the shell; empty holes are skipped
+func (b *builder) addPolygon(rings [][][]float64) error {
+	trimmed := make([][][]float64, 0, len(rings))
+	for _, ring := range rings {
+		if err := b.checkPositions(ring); err != nil {
+			return err
+		}
+		// GeoJSON rings repeat their first position at the end
+		if n := len(ring); n > 1 && slices.Equal(ring[0], ring[n-1]) {
+			ring = ring[:n-1]
+		}
+		if len(ring) > 0 {
+			trimmed = append(trimmed, ring)
+		}
+	}
+	if len(rings) == 0 || len(rings[0]) == 0 {
+		return nil
+	}
+
+	vertices, holes, dim := earcut.Flatten(trimmed)
+	b.polygons = append(b.polygons, earcut.Polygon{Data: vertices, Holes: holes, Dim: dim})
+	b.polygonFeatures = append(b.polygonFeatures, b.feature)
+	return nil
+}
+
+// check every position has the mesh's dimension, setting it on first use
+func (b *builder) checkPositions(ring [][]float64) error {
+	for _, p := range ring {
+		if len(p) < 2 {
+			return fmt.Errorf("%w: %v", ErrBadPosition, p)
+		}
+		if b.mesh.Dim == 0 {
+			b.mesh.Dim = len(p)
+		}
+		if len(p) != b.mesh.Dim {
+			return fmt.Errorf("%w: %d and %d", ErrMixedDimensions, b.mesh.Dim, len(p))
+		}
+	}
+	return nil
+}
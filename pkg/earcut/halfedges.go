@@ -0,0 +1,89 @@
+package earcut
+
+// Half-edge e runs from vertex triangles[e] to triangles[NextHalfedge(e)] and belongs to
+// triangle e/3; its twin in the neighbouring triangle runs the other way.
+
+// NextHalfedge returns the half-edge after e in the same triangle.
+func NextHalfedge(e int) int {
+	if e%3 == 2 {
+		return e - 2
+	}
+	return e + 1
+}
+
+// PrevHalfedge returns the half-edge before e in the same triangle.
+func PrevHalfedge(e int) int {
+	if e%3 == 0 {
+		return e + 2
+	}
+	return e - 1
+}
+
+// Halfedges pairs every half-edge of triangles, as returned by Earcut, with its twin: the
+// result holds at index e the half-edge running the other way along the same edge, or -1 when
+// e is on the boundary. Edges used more than once in the same direction, as around a vertex
+// where holes touch, are left unpaired too.
+func Halfedges(triangles []int) []int {
+	halfedges := make([]int, len(triangles))
+	seen := make(map[[2]int]int, len(triangles))
+	for e := range halfedges {
+		halfedges[e] = -1
+		key := [2]int{triangles[e], triangles[NextHalfedge(e)]}
+		if _, ok := seen[key]; ok {
+			seen[key] = -1
+		} else {
+			seen[key] = e
+		}
+	}
+	for e := range halfedges {
+		twin, ok := seen[[2]int{triangles[NextHalfedge(e)], triangles[e]}]
+		if ok && twin >= 0 && seen[[2]int{triangles[e], triangles[NextHalfedge(e)]}] == e {
+			halfedges[e] = twin
+		}
+	}
+	return halfedges
+}
+
+// Neighbors returns for every triangle the triangles across its three edges: index 3*t+k holds
+// the triangle sharing the edge from triangles[3*t+k] to the next vertex of t, or -1 when that
+// edge is on the boundary.
+func Neighbors(triangles []int) []int {
+	neighbors := Halfedges(triangles)
+	for e, twin := range neighbors {
+		if twin >= 0 {
+			neighbors[e] = twin / 3
+		}
+	}
+	return neighbors
+}
+
+// Boundary returns the boundary of the mesh given by triangles and their Halfedges as closed
+// loops of half-edges, each running with the mesh on its left: one loop for the outer ring and
+// one for each hole, unless holes touch the outline or each other. The vertices of a loop are
+// triangles[e] for each e in it.
+func Boundary(triangles, halfedges []int) [][]int {
+	var loops [][]int
+	visited := make([]bool, len(halfedges))
+	for start, twin := range halfedges {
+		if twin >= 0 || visited[start] {
+			continue
+		}
+		var loop []int
+		for e := start; !visited[e]; {
+			visited[e] = true
+			loop = append(loop, e)
+
+			// turn around the end vertex to the next boundary half-edge leaving it
+			next := NextHalfedge(e)
+			for steps := 0; halfedges[next] >= 0 && steps < len(halfedges); steps++ {
+				next = NextHalfedge(halfedges[next])
+			}
+			if halfedges[next] >= 0 {
+				break
+			}
+			e = next
+		}
+		loops = append(loops, loop)
+	}
+	return loops
+}
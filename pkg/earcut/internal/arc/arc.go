@@ -0,0 +1,34 @@
+// Package arc flattens circular arcs for the packages of earcut that draw them, svgpath and the
+// stroker.
+package arc
+
+import "math"
+
+// most chords Segments returns, as many as halving a curve 16 times gives
+const maxSegments = 1 << 16
+
+// Segments returns how many equal chords approximate an arc of the given radius spanning
+// angle radians (either sign) while staying within tolerance of it, at least 1 and at most
+// 65536 so that a tolerance far below the radius still gives a finite, round arc.
+func Segments(radius, angle, tolerance float64) int {
+	angle = math.Abs(angle)
+	if !(radius > 0) || !(tolerance > 0) || !(angle > 0) {
+		return 1
+	}
+	// a chord spanning angle a sits r(1 - cos(a/2)) away from the arc at most; for small ratios
+	// 1 - ratio rounds towards 1 and acos towards 0, so use 1 - cos(x) ~ x^2/2 instead
+	var step float64
+	switch ratio := tolerance / radius; {
+	case ratio >= 1:
+		step = math.Pi
+	case ratio < 1e-4:
+		step = 2 * math.Sqrt(2*ratio)
+	default:
+		step = 2 * math.Acos(1-ratio)
+	}
+	n := math.Ceil(angle / step)
+	if !(n < maxSegments) {
+		return maxSegments
+	}
+	return int(math.Max(n, 1))
+}
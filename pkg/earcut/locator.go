@@ -0,0 +1,153 @@
+package earcut
+
+import "math"
+
+// Locator finds the triangle of a triangulation that contains a point. It buckets the
+// triangles into a uniform grid over their bounding box, about one cell per triangle, so a
+// query only tests the few triangles overlapping the cell of the point.
+// A Locator is safe for concurrent queries.
+type Locator struct {
+	data      []float64
+	dim       int
+	triangles []int
+
+	minX, minY, maxX, maxY float64
+	cols, rows             int
+	// cell sizes, inverted
+	invW, invH float64
+	// the triangles overlapping cell c are cellTriangles[cellStart[c]:cellStart[c+1]]
+	cellStart, cellTriangles []int
+}
+
+// NewLocator builds a Locator for triangles, as returned by Earcut, over the vertices in data
+// with dim coordinates each. Only x and y are used. The slices are kept, not copied.
+func NewLocator(data []float64, dim int, triangles []int) *Locator {
+	if dim == 0 {
+		dim = 2
+	}
+	l := &Locator{data: data, dim: dim, triangles: triangles,
+		minX: math.Inf(1), minY: math.Inf(1), maxX: math.Inf(-1), maxY: math.Inf(-1)}
+	n := len(triangles) / 3
+	if n == 0 {
+		return l
+	}
+	for _, i := range triangles {
+		l.minX, l.maxX = math.Min(l.minX, data[i*dim]), math.Max(l.maxX, data[i*dim])
+		l.minY, l.maxY = math.Min(l.minY, data[i*dim+1]), math.Max(l.maxY, data[i*dim+1])
+	}
+
+	// about one cell per triangle, shaped like the bounding box
+	width, height := l.maxX-l.minX, l.maxY-l.minY
+	switch {
+	case width > 0 && height > 0:
+		l.cols = int(math.Ceil(math.Sqrt(float64(n) * width / height)))
+		l.rows = int(math.Ceil(float64(n) / float64(l.cols)))
+	case width > 0:
+		l.cols, l.rows = n, 1
+	case height > 0:
+		l.cols, l.rows = 1, n
+	default:
+		l.cols, l.rows = 1, 1
+	}
+	l.cols, l.rows = clamp(l.cols, 1, n), clamp(l.rows, 1, n)
+	if width > 0 {
+		l.invW = float64(l.cols) / width
+	}
+	if height > 0 {
+		l.invH = float64(l.rows) / height
+	}
+
+	// count the triangles per cell, then fill them in
+	l.cellStart = make([]int, l.cols*l.rows+1)
+	for pass := 0; pass < 2; pass++ {
+		for t := 0; t < n; t++ {
+			c0, r0, c1, r1 := l.cellRange(t)
+			for r := r0; r <= r1; r++ {
+				for c := c0; c <= c1; c++ {
+					cell := r*l.cols + c
+					if pass == 0 {
+						l.cellStart[cell+1]++
+					} else {
+						l.cellTriangles[l.cellStart[cell]] = t
+						l.cellStart[cell]++
+					}
+				}
+			}
+		}
+		if pass == 0 {
+			for c := 1; c < len(l.cellStart); c++ {
+				l.cellStart[c] += l.cellStart[c-1]
+			}
+			l.cellTriangles = make([]int, l.cellStart[len(l.cellStart)-1])
+		}
+	}
+	// filling advanced every start to the next one; shift them back
+	copy(l.cellStart[1:], l.cellStart[:len(l.cellStart)-1])
+	l.cellStart[0] = 0
+	return l
+}
+
+// the columns and rows of the cells the bounding box of triangle t overlaps
+func (l *Locator) cellRange(t int) (c0, r0, c1, r1 int) {
+	a, b, c := l.triangles[3*t]*l.dim, l.triangles[3*t+1]*l.dim, l.triangles[3*t+2]*l.dim
+	c0, r0 = l.cell(min3(l.data[a], l.data[b], l.data[c]), min3(l.data[a+1], l.data[b+1], l.data[c+1]))
+	c1, r1 = l.cell(max3(l.data[a], l.data[b], l.data[c]), max3(l.data[a+1], l.data[b+1], l.data[c+1]))
+	return c0, r0, c1, r1
+}
+
+// the column and row of the cell containing (x, y), clamped to the grid
+func (l *Locator) cell(x, y float64) (int, int) {
+	c := int((x - l.minX) * l.invW)
+	r := int((y - l.minY) * l.invH)
+	return clamp(c, 0, l.cols-1), clamp(r, 0, l.rows-1)
+}
+
+func clamp(v, lo, hi int) int {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
+
+// Locate returns the index t of a triangle containing (x, y), so that its vertices are
+// triangles[3*t], triangles[3*t+1] and triangles[3*t+2], and the barycentric coordinates of the
+// point relative to them. Points on an edge shared by two triangles may be reported in either.
+// ok is false when no triangle contains the point.
+func (l *Locator) Locate(x, y float64) (t int, bary [3]float64, ok bool) {
+	if len(l.cellStart) == 0 || !(x >= l.minX && x <= l.maxX && y >= l.minY && y <= l.maxY) {
+		return -1, bary, false
+	}
+	col, row := l.cell(x, y)
+	cell := row*l.cols + col
+	for _, t := range l.cellTriangles[l.cellStart[cell]:l.cellStart[cell+1]] {
+		a, b, c := l.triangles[3*t]*l.dim, l.triangles[3*t+1]*l.dim, l.triangles[3*t+2]*l.dim
+		ax, ay := l.data[a], l.data[a+1]
+		bx, by := l.data[b], l.data[b+1]
+		cx, cy := l.data[c], l.data[c+1]
+
+		// the signs are exact, so a point on a shared edge is never missed by both triangles
+		area := Orient2D(ax, ay, bx, by, cx, cy)
+		if area == 0 {
+			continue
+		}
+		wa := Orient2D(x, y, bx, by, cx, cy)
+		wb := Orient2D(ax, ay, x, y, cx, cy)
+		wc := Orient2D(ax, ay, bx, by, x, y)
+		if area < 0 {
+			area, wa, wb, wc = -area, -wa, -wb, -wc
+		}
+		if wa >= 0 && wb >= 0 && wc >= 0 {
+			return t, [3]float64{wa / area, wb / area, wc / area}, true
+		}
+	}
+	return -1, bary, false
+}
+
+// Contains reports whether (x, y) lies inside the triangulated polygon or on its boundary.
+func (l *Locator) Contains(x, y float64) bool {
+	_, _, ok := l.Locate(x, y)
+	return ok
+}
@@ -0,0 +1,121 @@
+package earcut
+
+// mesh is a triangulation being edited in place: triangles and their half-edge pairing over
+// vertex coordinates with dim values per vertex, of which x and y are used
+type mesh struct {
+	data      []float64
+	dim       int
+	triangles []int
+	halfedges []int
+	// triangles created or changed since the caller last cleared it
+	touched []int
+	stack   []int
+}
+
+func (m *mesh) x(i int) float64 { return m.data[i*m.dim] }
+func (m *mesh) y(i int) float64 { return m.data[i*m.dim+1] }
+
+// orientation of vertices i, j and k, positive when counterclockwise
+func (m *mesh) orient(i, j, k int) float64 {
+	return Orient2D(m.x(i), m.y(i), m.x(j), m.y(j), m.x(k), m.y(k))
+}
+
+// flip the paired edges on m.stack, and those next to each flip, until none is illegal;
+// returns the number of flips
+func (m *mesh) legalize() int {
+	triangles, halfedges := m.triangles, m.halfedges
+	flips := 0
+	for len(m.stack) > 0 {
+		a := m.stack[len(m.stack)-1]
+		m.stack = m.stack[:len(m.stack)-1]
+		b := halfedges[a]
+		if b < 0 {
+			continue
+		}
+
+		// a runs from pr to pl with p0 opposite it; b runs back with p1 opposite
+		al, ar := NextHalfedge(a), PrevHalfedge(a)
+		br, bl := NextHalfedge(b), PrevHalfedge(b)
+		pr, pl, p0, p1 := triangles[a], triangles[al], triangles[ar], triangles[bl]
+
+		if !inCircle(m.x(p0), m.y(p0), m.x(pr), m.y(pr), m.x(pl), m.y(pl), m.x(p1), m.y(p1)) ||
+			!convexQuad(m.x(p0), m.y(p0), m.x(pr), m.y(pr), m.x(p1), m.y(p1), m.x(pl), m.y(pl)) {
+			continue
+		}
+
+		// replace diagonal pr-pl with p0-p1
+		triangles[a] = p1
+		triangles[b] = p0
+		m.link(a, halfedges[bl])
+		m.link(b, halfedges[ar])
+		m.link(ar, bl)
+		flips++
+
+		m.touched = append(m.touched, a/3, b/3)
+		m.stack = append(m.stack, a, al, b, br)
+	}
+	return flips
+}
+
+// set e and twin as each other's twin, twin may be -1
+func (m *mesh) link(e, twin int) {
+	m.halfedges[e] = twin
+	if twin >= 0 {
+		m.halfedges[twin] = e
+	}
+}
+
+// add triangle abc with its half-edges' twins and return its first half-edge
+func (m *mesh) addTriangle(a, b, c, ab, bc, ca int) int {
+	t := len(m.triangles)
+	m.triangles = append(m.triangles, a, b, c)
+	m.halfedges = append(m.halfedges, -1, -1, -1)
+	m.link(t, ab)
+	m.link(t+1, bc)
+	m.link(t+2, ca)
+	m.touched = append(m.touched, t/3)
+	return t
+}
+
+// insert vertex p inside the triangle of half-edge e, splitting it in three
+func (m *mesh) splitTriangle(e, p int) {
+	t := e - e%3
+	a, b, c := m.triangles[t], m.triangles[t+1], m.triangles[t+2]
+	bcTwin, caTwin := m.halfedges[t+1], m.halfedges[t+2]
+
+	// t becomes abp, followed by bcp and cap
+	m.triangles[t+2] = p
+	m.touched = append(m.touched, t/3)
+	bcp := m.addTriangle(b, c, p, bcTwin, -1, t+1)
+	m.addTriangle(c, a, p, caTwin, t+2, bcp+1)
+
+	m.stack = append(m.stack, t, bcp, bcp+3)
+	m.legalize()
+}
+
+// insert vertex p on the edge of half-edge e, splitting the triangles on both sides in two
+func (m *mesh) splitEdge(e, p int) {
+	twin := m.halfedges[e]
+
+	// e runs from a to b with c opposite: abc becomes apc and pbc
+	e1, e2 := NextHalfedge(e), PrevHalfedge(e)
+	b, c := m.triangles[e1], m.triangles[e2]
+	bcTwin := m.halfedges[e1]
+	m.triangles[e1] = p
+	m.touched = append(m.touched, e/3)
+	pbc := m.addTriangle(p, b, c, -1, bcTwin, e1)
+	m.stack = append(m.stack, e2, pbc+1)
+
+	if twin >= 0 {
+		// twin runs from b to a with d opposite: bad becomes bpd and pad
+		o1, o2 := NextHalfedge(twin), PrevHalfedge(twin)
+		a, d := m.triangles[o1], m.triangles[o2]
+		adTwin := m.halfedges[o1]
+		m.triangles[o1] = p
+		m.touched = append(m.touched, twin/3)
+		pad := m.addTriangle(p, a, d, e, adTwin, o1)
+		m.link(twin, pbc)
+		m.stack = append(m.stack, o2, pad+1)
+	}
+	m.legalize()
+}
@@ -0,0 +1,85 @@
+package earcut
+
+// defaultHashThreshold is the vertex count above which HashAuto enables z-order hashing
+const defaultHashThreshold = 80
+
+// HashMode selects when the z-order curve hash is used to find points inside candidate ears.
+type HashMode int
+
+const (
+	// HashAuto hashes polygons with more than Options.HashThreshold vertices.
+	HashAuto HashMode = iota
+	// HashAlways hashes every polygon.
+	HashAlways
+	// HashNever always scans the whole ring, which is faster for small polygons.
+	HashNever
+)
+
+// Pass identifies the last stage the ear slicing loop may fall back to when it runs out of ears.
+type Pass int
+
+const (
+	// PassAll runs every stage, same as PassSplit.
+	PassAll Pass = iota
+	// PassEars only slices ears and gives up as soon as none is left.
+	PassEars
+	// PassFilter also retries after removing collinear and duplicate points.
+	PassFilter
+	// PassCure also retries after curing small local self-intersections.
+	PassCure
+	// PassSplit also splits the remaining polygon in two as a last resort.
+	PassSplit
+)
+
+// Options tunes EarcutWithOptions. The zero value behaves like Earcut.
+type Options struct {
+	// Hashing selects when the z-order curve hash is used.
+	Hashing HashMode
+	// HashThreshold is the vertex count above which HashAuto hashes; 0 means 80.
+	HashThreshold int
+	// MaxPass caps the fallback stages, from PassEars to PassSplit; zero, or any value outside
+	// that range, runs them all.
+	MaxPass Pass
+	// DisableCure skips curing local self-intersections while still running the stages after it.
+	DisableCure bool
+	// MaxWork caps the steps (candidate ears, diagonals and hole bridges examined) a triangulation
+	// may take before it stops with the triangles found so far; zero means no limit. Each step
+	// costs at most time linear in the polygon size. Only the functions that return an error
+	// apply it, reporting the cut-off as ErrBudgetExceeded: EarcutContext, EarcutT and their
+	// Triangulator methods. EarcutWithOptions and Triangulator.Triangulate ignore it rather
+	// than return part of the triangles unannounced.
+	MaxWork int
+	// ProjectToPlane triangulates polygons with dim of 3 or more in the best-fit plane of their
+	// outer ring (found with Newell's method) instead of the xy plane, so vertical and tilted
+	// faces work. Triangles wind counterclockwise around the outer ring's normal and index the
+	// original vertices.
+	ProjectToPlane bool
+	// Robust evaluates every orientation and point-in-triangle test with exact-sign predicates
+	// (see Orient2D), which avoids overlapping triangles around nearly collinear vertices at
+	// large coordinates at a small cost in speed.
+	Robust bool
+}
+
+// whether a polygon with n vertices should be indexed in z-order
+func (e *earcutter) useHashing(n int) bool {
+	switch e.opts.Hashing {
+	case HashAlways:
+		return true
+	case HashNever:
+		return false
+	}
+	threshold := e.opts.HashThreshold
+	if threshold == 0 {
+		threshold = defaultHashThreshold
+	}
+	return n > threshold
+}
+
+// whether the ear slicing loop may enter the given pass (3 being the polygon split)
+func (e *earcutter) passEnabled(pass int) bool {
+	maxPass := e.opts.MaxPass
+	if maxPass < PassEars || maxPass > PassSplit {
+		maxPass = PassSplit
+	}
+	return Pass(pass+1) <= maxPass
+}
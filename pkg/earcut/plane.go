@@ -0,0 +1,55 @@
+package earcut
+
+import "math"
+
+// project the rings onto the best-fit plane of the outer ring, appending 2D coordinates to dst;
+// the plane's axes are chosen so that counterclockwise in 2D is counterclockwise around its normal
+func projectToPlane[F Float](dst []float64, data []F, holeIndices []int, dim int) []float64 {
+	outerLen := len(data)
+	if len(holeIndices) > 0 {
+		outerLen = holeIndices[0] * dim
+	}
+	nx, ny, nz := newellNormal(data, outerLen, dim)
+
+	length := math.Sqrt(nx*nx + ny*ny + nz*nz)
+	if length == 0 || math.IsInf(length, 0) {
+		// collinear or degenerate outer ring: keep the plain xy projection
+		nx, ny, nz, length = 0, 0, 1, 1
+	}
+	nx, ny, nz = nx/length, ny/length, nz/length
+
+	// u is the x axis, or the y axis for planes facing along x, with its normal component removed
+	ax, ay := 1.0, 0.0
+	if math.Abs(nx) > 0.9 {
+		ax, ay = 0, 1
+	}
+	d := ax*nx + ay*ny
+	ux, uy, uz := ax-d*nx, ay-d*ny, -d*nz
+	ul := math.Sqrt(ux*ux + uy*uy + uz*uz)
+	ux, uy, uz = ux/ul, uy/ul, uz/ul
+	// v = n × u
+	vx, vy, vz := ny*uz-nz*uy, nz*ux-nx*uz, nx*uy-ny*ux
+
+	for i := 0; i+2 < len(data); i += dim {
+		x, y, z := float64(data[i]), float64(data[i+1]), float64(data[i+2])
+		dst = append(dst, x*ux+y*uy+z*uz, x*vx+y*vy+z*vz)
+	}
+	return dst
+}
+
+// Newell's method for the normal of a possibly non-planar ring, relative to its first vertex
+// for precision; its length is twice the area of the ring's projection onto its plane
+func newellNormal[F Float](data []F, end, dim int) (nx, ny, nz float64) {
+	if end < 3*dim {
+		return 0, 0, 0
+	}
+	x0, y0, z0 := float64(data[0]), float64(data[1]), float64(data[2])
+	for i, j := 0, end-dim; i < end; j, i = i, i+dim {
+		xi, yi, zi := float64(data[i])-x0, float64(data[i+1])-y0, float64(data[i+2])-z0
+		xj, yj, zj := float64(data[j])-x0, float64(data[j+1])-y0, float64(data[j+2])-z0
+		nx += (yj - yi) * (zj + zi)
+		ny += (zj - zi) * (xj + xi)
+		nz += (xj - xi) * (yj + yi)
+	}
+	return nx, ny, nz
+}
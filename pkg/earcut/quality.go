@@ -0,0 +1,338 @@
+package earcut
+
+import "math"
+
+// QualityOptions sets the targets of RefineQuality.
+type QualityOptions struct {
+	// MinAngle is the smallest interior angle, in degrees, a triangle may have. Refinement is
+	// guaranteed to end for values up to about 20 as long as the polygon has no sharper corners,
+	// and usually does up to about 33.
+	MinAngle float64
+	// MaxArea is the largest area a triangle may have; 0 means no limit.
+	MaxArea float64
+	// MaxSteiner caps the number of vertices added; 0 means no limit. Without it, crossing or
+	// touching rings are refined down to the limit of floating-point precision where they meet.
+	MaxSteiner int
+}
+
+// RefineQuality triangulates the polygon like Earcut and then inserts Steiner points, Ruppert
+// style, until every triangle meets opts or the points run out. Circumcenters of bad
+// triangles are added inside, and ring edges they would encroach on are split at their
+// midpoints instead. It returns data with the new vertices appended, their extra coordinates
+// beyond x and y interpolated from the triangle or edge they fall on, and triangle indices into it.
+func RefineQuality(data []float64, holeIndices []int, dim int, opts QualityOptions) ([]float64, []int) {
+	if dim == 0 {
+		dim = 2
+	}
+	triangles := Earcut(data, holeIndices, dim)
+	vertices := append([]float64(nil), data...)
+	if len(triangles) == 0 {
+		return vertices, triangles
+	}
+
+	halfedges := Halfedges(triangles)
+	constrained := ringEdges(len(data)/dim, holeIndices)
+	for e, twin := range halfedges {
+		if twin >= 0 && constrained[edgeKey(triangles[e], triangles[NextHalfedge(e)])] {
+			halfedges[e] = -1
+			halfedges[twin] = -1
+		}
+	}
+
+	q := &refiner{
+		mesh:    mesh{data: vertices, dim: dim, triangles: triangles, halfedges: halfedges},
+		opts:    opts,
+		inputs:  len(data) / dim,
+		onInput: map[int][2]int{},
+	}
+	for e := len(triangles) - 1; e >= 0; e-- {
+		if halfedges[e] > e {
+			q.stack = append(q.stack, e)
+		}
+	}
+	q.legalize()
+
+	// points closer than this can't be told apart reliably, so edges this short aren't split
+	minX, minY, maxX, maxY := math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)
+	for i := 0; i < len(data); i += dim {
+		minX, maxX = math.Min(minX, data[i]), math.Max(maxX, data[i])
+		minY, maxY = math.Min(minY, data[i+1]), math.Max(maxY, data[i+1])
+	}
+	q.minLength = math.Hypot(maxX-minX, maxY-minY) * 1e-9
+
+	q.run()
+	return q.data, q.triangles
+}
+
+// refiner holds the work lists of RefineQuality
+type refiner struct {
+	mesh
+	opts      QualityOptions
+	minLength float64
+	added     int
+	// boundary half-edges and triangles to check, possibly stale
+	segments, bad []int
+	// the input ring edge each vertex added on the boundary lies on
+	inputs  int
+	onInput map[int][2]int
+}
+
+func (q *refiner) run() {
+	q.touched = q.touched[:0]
+	for t := 0; t < len(q.triangles)/3; t++ {
+		q.queue(t)
+	}
+
+	for len(q.segments) > 0 || len(q.bad) > 0 {
+		if q.opts.MaxSteiner > 0 && q.added >= q.opts.MaxSteiner {
+			return
+		}
+
+		if len(q.segments) > 0 {
+			e := q.segments[len(q.segments)-1]
+			q.segments = q.segments[:len(q.segments)-1]
+			if q.halfedges[e] < 0 && q.encroached(e, q.triangles[PrevHalfedge(e)]) {
+				q.splitSegment(e)
+			}
+		} else {
+			t := q.bad[len(q.bad)-1]
+			q.bad = q.bad[:len(q.bad)-1]
+			if q.isBad(t) {
+				q.splitBad(t)
+			}
+		}
+
+		for _, t := range q.touched {
+			q.queue(t)
+		}
+		q.touched = q.touched[:0]
+	}
+}
+
+// add triangle t and its boundary edges to the work lists
+func (q *refiner) queue(t int) {
+	q.bad = append(q.bad, t)
+	for e := 3 * t; e < 3*t+3; e++ {
+		if q.halfedges[e] < 0 {
+			q.segments = append(q.segments, e)
+		}
+	}
+}
+
+// whether vertex p lies inside the circle with the edge of half-edge e as its diameter
+func (q *refiner) encroached(e, p int) bool {
+	if p == q.triangles[e] || p == q.triangles[NextHalfedge(e)] {
+		return false
+	}
+	return q.encroachedAt(e, q.x(p), q.y(p))
+}
+
+// whether (x, y) lies inside the circle with the edge of half-edge e as its diameter
+func (q *refiner) encroachedAt(e int, x, y float64) bool {
+	a, b := q.triangles[e], q.triangles[NextHalfedge(e)]
+	return (q.x(a)-x)*(q.x(b)-x)+(q.y(a)-y)*(q.y(b)-y) < 0
+}
+
+func (q *refiner) length(i, j int) float64 {
+	return math.Hypot(q.x(j)-q.x(i), q.y(j)-q.y(i))
+}
+
+// whether triangle t misses the angle or area target, ignoring triangles too small to split
+func (q *refiner) isBad(t int) bool {
+	a, b, c := q.triangles[3*t], q.triangles[3*t+1], q.triangles[3*t+2]
+	if min3(q.length(a, b), q.length(b, c), q.length(c, a)) < q.minLength || q.orient(a, b, c) <= 0 {
+		return false
+	}
+	if q.opts.MaxArea > 0 && q.orient(a, b, c)/2 > q.opts.MaxArea {
+		return true
+	}
+	if MinAngle(q.data, q.dim, q.triangles[3*t:3*t+3]) >= q.opts.MinAngle {
+		return false
+	}
+
+	// leave alone skinny triangles cut off by a sharp input corner: splitting them would only
+	// add ever smaller ones around it
+	u, v := a, b
+	if q.length(b, c) < q.length(u, v) {
+		u, v = b, c
+	}
+	if q.length(c, a) < q.length(u, v) {
+		u, v = c, a
+	}
+	su, okU := q.onInput[u]
+	sv, okV := q.onInput[v]
+	if okU && okV && su != sv {
+		// the edges may meet at a shared vertex or at two vertices in the same place
+		for _, apex := range su {
+			for k, other := range sv {
+				if q.x(apex) != q.x(other) || q.y(apex) != q.y(other) {
+					continue
+				}
+				i, j := su[0]+su[1]-apex, sv[1-k]
+				angle := math.Abs(math.Atan2(q.orient(apex, i, j), (q.x(i)-q.x(apex))*(q.x(j)-q.x(apex))+(q.y(i)-q.y(apex))*(q.y(j)-q.y(apex))))
+				if angle < math.Pi/3 {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+// split the boundary edge of half-edge e at its midpoint
+func (q *refiner) splitSegment(e int) bool {
+	a, b := q.triangles[e], q.triangles[NextHalfedge(e)]
+	if q.length(a, b) < 2*q.minLength {
+		return false
+	}
+	p := len(q.data) / q.dim
+	switch {
+	case a >= q.inputs:
+		q.onInput[p] = q.onInput[a]
+	case b >= q.inputs:
+		q.onInput[p] = q.onInput[b]
+	default:
+		q.onInput[p] = edgeKey(a, b)
+	}
+	for k := 0; k < q.dim; k++ {
+		q.data = append(q.data, (q.data[a*q.dim+k]+q.data[b*q.dim+k])/2)
+	}
+	q.splitEdge(e, p)
+	q.added++
+	return true
+}
+
+// insert the circumcenter of triangle t, or split a boundary edge it would encroach on and
+// check t again afterwards
+func (q *refiner) splitBad(t int) {
+	a, b, c := q.triangles[3*t], q.triangles[3*t+1], q.triangles[3*t+2]
+	cx, cy := circumcenter(q.x(a), q.y(a), q.x(b), q.y(b), q.x(c), q.y(c))
+
+	e, where := q.locate(3*t, cx, cy)
+	switch where {
+	case lost:
+		return
+	case blocked:
+		// the circumcenter lies outside, beyond a boundary edge; splitting that edge only helps
+		// if the circumcenter encroaches on it
+		if q.encroachedAt(e, cx, cy) && q.splitSegment(e) {
+			q.touched = append(q.touched, t)
+		}
+		return
+	}
+
+	// the boundary edges of the triangles whose circumcircles contain the circumcenter are
+	// those it could encroach on
+	cavity := map[int]bool{e / 3: true}
+	stack := []int{e / 3}
+	for len(stack) > 0 {
+		s := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for h := 3 * s; h < 3*s+3; h++ {
+			i, j := q.triangles[h], q.triangles[NextHalfedge(h)]
+			if q.halfedges[h] < 0 {
+				if q.encroachedAt(h, cx, cy) {
+					if q.splitSegment(h) {
+						q.touched = append(q.touched, t)
+					}
+					return
+				}
+				continue
+			}
+			n := q.halfedges[h] / 3
+			k := q.triangles[PrevHalfedge(q.halfedges[h])]
+			if !cavity[n] && inCircle(q.x(j), q.y(j), q.x(i), q.y(i), q.x(k), q.y(k), cx, cy) {
+				cavity[n] = true
+				stack = append(stack, n)
+			}
+		}
+	}
+
+	p := len(q.data) / q.dim
+	q.data = append(q.data, cx, cy)
+	q.data = append(q.data, q.interpolate(e-e%3, cx, cy)...)
+	if where == onEdge {
+		q.splitEdge(e, p)
+	} else {
+		q.splitTriangle(e, p)
+	}
+	q.added++
+}
+
+// coordinates beyond x and y at (x, y), interpolated linearly over the triangle of half-edge t
+func (q *refiner) interpolate(t int, x, y float64) []float64 {
+	if q.dim == 2 {
+		return nil
+	}
+	a, b, c := q.triangles[t], q.triangles[t+1], q.triangles[t+2]
+	area := q.orient(a, b, c)
+	wa := Orient2D(x, y, q.x(b), q.y(b), q.x(c), q.y(c)) / area
+	wb := Orient2D(q.x(a), q.y(a), x, y, q.x(c), q.y(c)) / area
+	wc := 1 - wa - wb
+	extra := make([]float64, q.dim-2)
+	for k := range extra {
+		extra[k] = wa*q.data[a*q.dim+2+k] + wb*q.data[b*q.dim+2+k] + wc*q.data[c*q.dim+2+k]
+	}
+	return extra
+}
+
+// where a point was found by locate
+type location int
+
+const (
+	// inside the triangle of the half-edge
+	inside location = iota
+	// on the half-edge
+	onEdge
+	// beyond the half-edge, which is on the boundary
+	blocked
+	// on a vertex, or the walk got lost
+	lost
+)
+
+// walk from the centroid of the triangle of half-edge e straight towards (x, y) and return
+// where the point lies relative to the half-edge the walk ends at
+func (q *refiner) locate(e int, x, y float64) (int, location) {
+	t := e - e%3
+	a, b, c := q.triangles[t], q.triangles[t+1], q.triangles[t+2]
+	sx, sy := (q.x(a)+q.x(b)+q.x(c))/3, (q.y(a)+q.y(b)+q.y(c))/3
+
+	for steps := 0; steps <= len(q.triangles); steps++ {
+		next, h := -1, -1
+		for k := t; k < t+3; k++ {
+			i, j := q.triangles[k], q.triangles[NextHalfedge(k)]
+			o := Orient2D(q.x(i), q.y(i), q.x(j), q.y(j), x, y)
+			if o == 0 {
+				if (q.x(i) == x && q.y(i) == y) || (q.x(j) == x && q.y(j) == y) {
+					return k, lost
+				}
+				h = k
+			}
+			// leave through the edge the line from the start crosses
+			if o < 0 && next < 0 || o < 0 && Orient2D(sx, sy, x, y, q.x(i), q.y(i)) <= 0 &&
+				Orient2D(sx, sy, x, y, q.x(j), q.y(j)) >= 0 {
+				next = k
+			}
+		}
+		if next < 0 {
+			if h >= 0 {
+				return h, onEdge
+			}
+			return t, inside
+		}
+		if q.halfedges[next] < 0 {
+			return next, blocked
+		}
+		t = q.halfedges[next] - q.halfedges[next]%3
+	}
+	return -1, lost
+}
+
+func circumcenter(ax, ay, bx, by, cx, cy float64) (float64, float64) {
+	bx, by = bx-ax, by-ay
+	cx, cy = cx-ax, cy-ay
+	bl := bx*bx + by*by
+	cl := cx*cx + cy*cy
+	d := 2 * (bx*cy - by*cx)
+	return ax + (cy*bl-by*cl)/d, ay + (bx*cl-cx*bl)/d
+}
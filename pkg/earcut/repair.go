@@ -0,0 +1,230 @@
+package earcut
+
+import (
+	"math"
+	"slices"
+)
+
+// Repair fixes what it can of the problems Validate reports and returns the result as
+// polygons ready for Earcut, as splitting a ring may leave more than one. Duplicate vertices
+// and spikes are dropped; rings that cross or touch themselves are split where they do into
+// simple rings, and pieces that enclose nothing are dropped. Pieces of the outer ring become
+// outer rings, wound counter-clockwise, except those wound against the largest piece and lying
+// inside another, which become holes. Holes are wound clockwise and go to the smallest outer
+// ring containing them; holes outside every outer ring are dropped. Holes crossing the outer
+// ring or each other are kept as they are. Vertices added where edges cross get their extra
+// coordinates beyond x and y interpolated along one of the edges.
+func Repair(data []float64, holeIndices []int, dim int) []Polygon {
+	if dim == 0 {
+		dim = 2
+	}
+
+	type piece struct {
+		ring []float64
+		area float64
+	}
+	var shells, holes []piece
+	for r, bounds := range splitRings(len(data)/dim, holeIndices) {
+		ring := slices.Clone(data[bounds[0]*dim : bounds[1]*dim])
+		// each split adds at most two vertices, so a ring can't need many more splits than it
+		// has vertices; the cap only guards against rounding making up new crossings forever
+		budget := 4*len(ring)/dim + 16
+
+		var pieces []piece
+		for _, p := range splitRing(ring, dim, &budget) {
+			if area := robustSignedArea(p, 0, len(p), dim); area != 0 {
+				pieces = append(pieces, piece{p, area})
+			}
+		}
+		if r > 0 {
+			holes = append(holes, pieces...)
+			continue
+		}
+
+		largest := 0
+		for i, p := range pieces {
+			if math.Abs(p.area) > math.Abs(pieces[largest].area) {
+				largest = i
+			}
+		}
+		for i, p := range pieces {
+			inverted := false
+			if (p.area > 0) != (pieces[largest].area > 0) {
+				for j, q := range pieces {
+					if j != i && (q.area > 0) == (pieces[largest].area > 0) {
+						if in, out := ringInRing(p.ring, q.ring, dim); in >= 0 && out < 0 {
+							inverted = true
+							break
+						}
+					}
+				}
+			}
+			if inverted {
+				holes = append(holes, p)
+			} else {
+				shells = append(shells, p)
+			}
+		}
+	}
+
+	polygons := make([]Polygon, len(shells))
+	for i, s := range shells {
+		if s.area < 0 {
+			reverseRing(s.ring, dim)
+		}
+		polygons[i] = Polygon{Data: s.ring, Dim: dim}
+	}
+	for _, h := range holes {
+		owner := -1
+		for i, s := range shells {
+			if in, out := ringInRing(h.ring, s.ring, dim); in >= 0 && out < 0 &&
+				(owner < 0 || math.Abs(s.area) < math.Abs(shells[owner].area)) {
+				owner = i
+			}
+		}
+		if owner < 0 {
+			continue
+		}
+		if h.area > 0 {
+			reverseRing(h.ring, dim)
+		}
+		p := &polygons[owner]
+		p.Holes = append(p.Holes, len(p.Data)/dim)
+		p.Data = append(p.Data, h.ring...)
+	}
+	return polygons
+}
+
+// split a flat ring where it crosses or touches itself into rings that don't, dropping
+// duplicate vertices and spikes; budget caps the number of splits
+func splitRing(ring []float64, dim int, budget *int) [][]float64 {
+	ring = gather(ring, dim, simplifyRing(ring, dim, 0, len(ring)/dim, nil))
+	n := len(ring) / dim
+	if n < 3 {
+		return nil
+	}
+	if *budget <= 0 {
+		return [][]float64{ring}
+	}
+
+	edges := make([]ringEdge, n)
+	for k := range edges {
+		edges[k] = ringEdge{k: k, n: n, a: k, b: (k + 1) % n}
+	}
+	found := false
+	var e, f ringEdge
+	var x, y float64
+	overlappingEdges(ring, dim, edges, func(e1, f1 ringEdge) {
+		if found || e1.adjacent(f1) {
+			return
+		}
+		if kind, px, py := segmentContact(ring, dim, e1.a, e1.b, f1.a, f1.b); kind != apart {
+			found, e, f, x, y = true, e1, f1, px, py
+		}
+	})
+	if !found {
+		return [][]float64{ring}
+	}
+	*budget--
+
+	// put the point on both edges, the later first so the earlier stays where it is
+	if e.k > f.k {
+		e, f = f, e
+	}
+	ring = insertOnEdge(ring, dim, f, x, y)
+	ring = insertOnEdge(ring, dim, e, x, y)
+
+	// cut the ring into the loops either side of the first two copies of the point, starting
+	// with the one holding the first vertex
+	var at []int
+	for i := 0; i < len(ring) && len(at) < 2; i += dim {
+		if ring[i] == x && ring[i+1] == y {
+			at = append(at, i)
+		}
+	}
+	if len(at) < 2 {
+		return [][]float64{ring}
+	}
+	inner := slices.Clone(ring[at[0]:at[1]])
+	outer := append(slices.Clone(ring[at[1]:]), ring[:at[0]]...)
+	return append(splitRing(outer, dim, budget), splitRing(inner, dim, budget)...)
+}
+
+// insert (x, y) into edge e of the flat ring unless it is one of the edge's ends
+func insertOnEdge(ring []float64, dim int, e ringEdge, x, y float64) []float64 {
+	a, b := ring[e.a*dim:e.a*dim+dim], ring[e.b*dim:e.b*dim+dim]
+	if a[0] == x && a[1] == y || b[0] == x && b[1] == y {
+		return ring
+	}
+	v := make([]float64, dim)
+	v[0], v[1] = x, y
+	if dim > 2 {
+		t := math.Hypot(x-a[0], y-a[1]) / math.Hypot(b[0]-a[0], b[1]-a[1])
+		for k := 2; k < dim; k++ {
+			v[k] = a[k] + t*(b[k]-a[k])
+		}
+	}
+	return slices.Insert(ring, (e.a+1)*dim, v...)
+}
+
+// the vertices of the ring from vertex start to end, skipping repeated vertices and the tips
+// of spikes, where the ring turns straight back; spike, if not nil, is called with each tip
+func simplifyRing(data []float64, dim, start, end int, spike func(tip int)) []int {
+	same := func(i, j int) bool { return data[i*dim] == data[j*dim] && data[i*dim+1] == data[j*dim+1] }
+	isSpike := func(a, b, c int) bool {
+		ax, ay, bx, by, cx, cy := data[a*dim], data[a*dim+1], data[b*dim], data[b*dim+1], data[c*dim], data[c*dim+1]
+		if Orient2D(ax, ay, bx, by, cx, cy) != 0 || (bx-ax)*(cx-bx)+(by-ay)*(cy-by) >= 0 {
+			return false
+		}
+		if spike != nil {
+			spike(b)
+		}
+		return true
+	}
+
+	var kept []int
+	for i := start; i < end; i++ {
+		if len(kept) > 0 && same(kept[len(kept)-1], i) {
+			continue
+		}
+		for len(kept) > 1 && isSpike(kept[len(kept)-2], kept[len(kept)-1], i) {
+			kept = kept[:len(kept)-1]
+		}
+		// dropping the tip may have uncovered the same point
+		if len(kept) > 0 && same(kept[len(kept)-1], i) {
+			continue
+		}
+		kept = append(kept, i)
+	}
+	// the same where the end meets the start
+	for len(kept) >= 3 {
+		last := len(kept) - 1
+		switch {
+		case same(kept[last], kept[0]) || isSpike(kept[last-1], kept[last], kept[0]):
+			kept = kept[:last]
+		case isSpike(kept[last], kept[0], kept[1]):
+			kept = kept[1:]
+		default:
+			return kept
+		}
+	}
+	return nil
+}
+
+// the vertices of a flat ring with the given indices
+func gather(ring []float64, dim int, indices []int) []float64 {
+	out := make([]float64, 0, len(indices)*dim)
+	for _, i := range indices {
+		out = append(out, ring[i*dim:i*dim+dim]...)
+	}
+	return out
+}
+
+// reverse the order of the vertices of a flat ring in place
+func reverseRing(ring []float64, dim int) {
+	for i, j := 0, len(ring)-dim; i < j; i, j = i+dim, j-dim {
+		for k := 0; k < dim; k++ {
+			ring[i+k], ring[j+k] = ring[j+k], ring[i+k]
+		}
+	}
+}
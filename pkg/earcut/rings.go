@@ -0,0 +1,202 @@
+package earcut
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// FillRule decides which parts of the plane a set of possibly nested rings fills.
+type FillRule int
+
+const (
+	// EvenOdd fills the points that lie inside an odd number of rings, so holes and islands
+	// alternate with depth whatever their winding.
+	EvenOdd FillRule = iota
+	// NonZero fills the points around which the rings wind a nonzero number of times, counting
+	// counter-clockwise rings +1 and clockwise rings -1, so a ring only makes a hole when it
+	// winds against the ring around it.
+	NonZero
+)
+
+// ClassifyRings sorts an unordered set of rings, each flat with dim coordinates per vertex,
+// into the polygons they bound under rule. Rings may nest to any depth but must not cross each
+// other. Each polygon is returned as the indices of its rings in rings, outer ring first and
+// then its holes; rings that enclose nothing or, under NonZero, separate two filled regions
+// are left out. It returns nil if a ring does not hold whole finite vertices; ClassifyRingsE
+// reports why.
+func ClassifyRings(rings [][]float64, dim int, rule FillRule) [][]int {
+	polygons, _ := ClassifyRingsE(rings, dim, rule)
+	return polygons
+}
+
+// ClassifyRingsE is like ClassifyRings but validates every ring like EarcutE validates data and
+// returns an error wrapping one of the Err* values if one cannot be walked safely.
+func ClassifyRingsE(rings [][]float64, dim int, rule FillRule) ([][]int, error) {
+	if dim == 0 {
+		dim = 2
+	}
+	if err := checkRings(rings, dim); err != nil {
+		return nil, err
+	}
+	return classifyRings(rings, dim, rule), nil
+}
+
+// sort valid rings into polygons, see ClassifyRings
+func classifyRings(rings [][]float64, dim int, rule FillRule) [][]int {
+
+	// take the rings from the largest down, so each one's container is already placed
+	areas := make([]float64, len(rings))
+	var order []int
+	for i, ring := range rings {
+		if len(ring) >= 3*dim {
+			areas[i] = robustSignedArea(ring, 0, len(ring), dim)
+		}
+		if areas[i] != 0 {
+			order = append(order, i)
+		}
+	}
+	sort.SliceStable(order, func(a, b int) bool { return math.Abs(areas[order[a]]) > math.Abs(areas[order[b]]) })
+
+	type node struct {
+		ring int
+		box  [4]float64
+		// winding number just inside the ring, and the polygon its inside belongs to, or -1
+		winding, polygon int
+	}
+	filled := func(w int) bool {
+		if rule == EvenOdd {
+			return w%2 != 0
+		}
+		return w != 0
+	}
+	var placed []node
+	var polygons [][]int
+	for _, i := range order {
+		n := node{ring: i, box: ringBox(rings[i], dim), polygon: -1}
+
+		// the innermost ring around this one is the smallest placed one containing it
+		parent := -1
+		for p := len(placed) - 1; p >= 0; p-- {
+			if boxContains(placed[p].box, n.box) && ringInside(rings[i], rings[placed[p].ring], dim) {
+				parent = p
+				break
+			}
+		}
+		outside, owner := 0, -1
+		if parent >= 0 {
+			outside, owner = placed[parent].winding, placed[parent].polygon
+		}
+		n.winding = outside + 1
+		if rule == NonZero && areas[i] < 0 {
+			n.winding = outside - 1
+		}
+
+		switch {
+		case filled(n.winding) && !filled(outside):
+			n.polygon = len(polygons)
+			polygons = append(polygons, []int{i})
+		case !filled(n.winding) && filled(outside):
+			polygons[owner] = append(polygons[owner], i)
+		case filled(n.winding):
+			n.polygon = owner
+		}
+		placed = append(placed, n)
+	}
+	return polygons
+}
+
+// EarcutRings triangulates an unordered set of rings, each flat with dim coordinates per
+// vertex, sorted into polygons by ClassifyRings. It returns the rings joined into one vertex
+// array, in the order given, and the triangles of all the polygons as indices into it. It
+// returns nothing if a ring does not hold whole finite vertices; EarcutRingsE reports why.
+func EarcutRings(rings [][]float64, dim int, rule FillRule) (vertices []float64, triangles []int) {
+	vertices, triangles, _ = EarcutRingsE(rings, dim, rule)
+	return vertices, triangles
+}
+
+// EarcutRingsE is like EarcutRings but validates the rings like ClassifyRingsE and returns an
+// error wrapping one of the Err* values if one cannot be triangulated.
+func EarcutRingsE(rings [][]float64, dim int, rule FillRule) (vertices []float64, triangles []int, err error) {
+	if dim == 0 {
+		dim = 2
+	}
+	if err := checkRings(rings, dim); err != nil {
+		return nil, nil, err
+	}
+	first := make([]int, len(rings))
+	for i, ring := range rings {
+		first[i] = len(vertices) / dim
+		vertices = append(vertices, ring...)
+	}
+
+	var t Triangulator
+	var data []float64
+	var holes, local []int
+	for _, polygon := range classifyRings(rings, dim, rule) {
+		data, holes = data[:0], holes[:0]
+		for k, r := range polygon {
+			if k > 0 {
+				holes = append(holes, len(data)/dim)
+			}
+			data = append(data, rings[r]...)
+		}
+		local = t.Triangulate(local[:0], data, holes, dim)
+
+		// map the indices back into the joined rings
+		for _, v := range local {
+			k := sort.SearchInts(holes, v+1)
+			start := 0
+			if k > 0 {
+				start = holes[k-1]
+			}
+			triangles = append(triangles, first[polygon[k]]+v-start)
+		}
+	}
+	return vertices, triangles, nil
+}
+
+// check every ring like checkInput checks the data of a polygon
+func checkRings(rings [][]float64, dim int) error {
+	if dim < 2 {
+		return fmt.Errorf("%w: got %d", ErrBadDimension, dim)
+	}
+	for i, ring := range rings {
+		if err := checkInput(ring, nil, dim); err != nil {
+			return fmt.Errorf("ring %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// whether flat ring inner lies inside flat ring outer, which it must not cross
+func ringInside(inner, outer []float64, dim int) bool {
+	in, out := ringInRing(inner, outer, dim)
+	if in >= 0 || out >= 0 {
+		return out < 0
+	}
+	// every vertex is on the outline; an edge of inner cutting across outer decides
+	for i, j := 0, len(inner)-dim; i < len(inner); j, i = i, i+dim {
+		switch pointInRing(outer, dim, (inner[i]+inner[j])/2, (inner[i+1]+inner[j+1])/2) {
+		case 1:
+			return true
+		case -1:
+			return false
+		}
+	}
+	return false
+}
+
+// the bounding box of a flat ring as min x, min y, max x, max y
+func ringBox(ring []float64, dim int) [4]float64 {
+	box := [4]float64{math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)}
+	for i := 0; i+1 < len(ring); i += dim {
+		box[0], box[1] = math.Min(box[0], ring[i]), math.Min(box[1], ring[i+1])
+		box[2], box[3] = math.Max(box[2], ring[i]), math.Max(box[3], ring[i+1])
+	}
+	return box
+}
+
+func boxContains(outer, inner [4]float64) bool {
+	return outer[0] <= inner[0] && outer[1] <= inner[1] && inner[2] <= outer[2] && inner[3] <= outer[3]
+}
@@ -0,0 +1,136 @@
+package earcut
+
+import "math"
+
+// bound on the relative error of orient2d's floating-point evaluation, from Shewchuk's
+// "Adaptive Precision Floating-Point Arithmetic and Fast Robust Geometric Predicates"
+const ccwErrBoundA = (3 + 16*epsilon) * epsilon
+
+// half of the distance between 1 and the next float64
+const epsilon = 1.0 / (1 << 53)
+
+// Orient2D returns a positive value if a, b and c are in counterclockwise order, a negative
+// value if they are clockwise and zero if they are collinear. The sign is always exact: the
+// determinant is first evaluated in floating point and recomputed with exact arithmetic
+// only when rounding could have changed its sign. The magnitude approximates twice the
+// triangle's area.
+func Orient2D(ax, ay, bx, by, cx, cy float64) float64 {
+	detLeft := (ax - cx) * (by - cy)
+	detRight := (ay - cy) * (bx - cx)
+	det := detLeft - detRight
+
+	var detSum float64
+	switch {
+	case detLeft > 0:
+		if detRight <= 0 {
+			return det
+		}
+		detSum = detLeft + detRight
+	case detLeft < 0:
+		if detRight >= 0 {
+			return det
+		}
+		detSum = -detLeft - detRight
+	default:
+		return det
+	}
+	if bound := ccwErrBoundA * detSum; det >= bound || -det >= bound {
+		return det
+	}
+	return orient2DExact(ax, ay, bx, by, cx, cy)
+}
+
+// evaluate the determinant as the exact sum of the six products it expands to
+func orient2DExact(ax, ay, bx, by, cx, cy float64) float64 {
+	var terms [12]float64
+	terms[0], terms[1] = twoProduct(ax, by)
+	terms[2], terms[3] = twoProduct(-ax, cy)
+	terms[4], terms[5] = twoProduct(-cx, by)
+	terms[6], terms[7] = twoProduct(-ay, bx)
+	terms[8], terms[9] = twoProduct(ay, cx)
+	terms[10], terms[11] = twoProduct(cy, bx)
+
+	// grow a nonoverlapping expansion, smallest component first, one term at a time
+	var expansion [13]float64
+	n := 0
+	for _, t := range terms {
+		q := t
+		m := 0
+		for _, c := range expansion[:n] {
+			var h float64
+			q, h = twoSum(q, c)
+			if h != 0 {
+				expansion[m] = h
+				m++
+			}
+		}
+		if q != 0 {
+			expansion[m] = q
+			m++
+		}
+		n = m
+	}
+
+	// the largest component dominates the rest, so summing upwards keeps the sign
+	var sum float64
+	for _, c := range expansion[:n] {
+		sum += c
+	}
+	return sum
+}
+
+// signedArea with a sign that can be trusted: when rounding could have flipped the sum, the
+// winding is read from the orientation of the ring at its lowest, then leftmost vertex instead
+func robustSignedArea[F Float](data []F, start, end, dim int) float64 {
+	var sum, magnitude float64
+	for i, j := start, end-dim; i < end; i += dim {
+		t := (float64(data[j]) - float64(data[i])) * (float64(data[i+1]) + float64(data[j+1]))
+		sum += t
+		magnitude += math.Abs(t)
+		j = i
+	}
+	// each term is off by at most 3 rounding errors and the sum adds one per term
+	n := float64((end - start) / dim)
+	if math.Abs(sum) > 2*(n+3)*epsilon*magnitude {
+		return sum
+	}
+
+	lowest := start
+	for i := start + dim; i < end; i += dim {
+		if data[i+1] < data[lowest+1] || data[i+1] == data[lowest+1] && data[i] < data[lowest] {
+			lowest = i
+		}
+	}
+	// neighbours of the lowest vertex, skipping its duplicates
+	same := func(i int) bool { return data[i] == data[lowest] && data[i+1] == data[lowest+1] }
+	prev, next := lowest, lowest
+	for k := 0; k < end-start && same(prev); k += dim {
+		if prev -= dim; prev < start {
+			prev = end - dim
+		}
+	}
+	for k := 0; k < end-start && same(next); k += dim {
+		if next += dim; next >= end {
+			next = start
+		}
+	}
+	if o := Orient2D(float64(data[prev]), float64(data[prev+1]), float64(data[lowest]), float64(data[lowest+1]),
+		float64(data[next]), float64(data[next+1])); o != 0 {
+		return o
+	}
+	return sum
+}
+
+// a + b as a rounded sum and its exact error
+func twoSum(a, b float64) (x, y float64) {
+	x = a + b
+	bv := x - a
+	av := x - bv
+	return x, (a - av) + (b - bv)
+}
+
+// a * b as a rounded product and its exact error
+func twoProduct(a, b float64) (x, y float64) {
+	x = a * b
+	return x, math.FMA(a, b, -x)
+}
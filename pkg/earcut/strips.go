@@ -0,0 +1,137 @@
+package earcut
+
+// Strips converts triangles, as returned by Earcut, into triangle strips by walking across the
+// edges they share. In strip s the k-th triangle is s[k], s[k+1], s[k+2] for even k and s[k+1],
+// s[k], s[k+2] for odd k, as OpenGL and Direct3D draw them, so every triangle keeps its winding.
+// Use RestartStrips or StitchStrips to join the strips into one index buffer.
+func Strips(triangles []int) [][]int {
+	halfedges := Halfedges(triangles)
+	n := len(triangles) / 3
+
+	// start from the triangles with the fewest neighbours, which are the hardest to reach
+	var buckets [4][]int
+	for t := 0; t < n; t++ {
+		neighbors := 0
+		for e := 3 * t; e < 3*t+3; e++ {
+			if halfedges[e] >= 0 {
+				neighbors++
+			}
+		}
+		buckets[neighbors] = append(buckets[neighbors], t)
+	}
+
+	used := make([]bool, n)
+	// the strip a triangle was last tried for, so a strip doesn't run into itself
+	stamp := make([]int, n)
+	tries := 0
+	// the vertices and triangles of the strip starting with half-edge e, following triangles
+	// not yet used
+	walk := func(e int, strip, tris []int) ([]int, []int) {
+		tries++
+		strip = append(strip[:0], triangles[e], triangles[NextHalfedge(e)], triangles[PrevHalfedge(e)])
+		tris = append(tris[:0], e/3)
+		stamp[e/3] = tries
+		for {
+			// leave across the edge between the last two vertices
+			u, v := strip[len(strip)-2], strip[len(strip)-1]
+			t := e - e%3
+			for k := t; k < t+3; k++ {
+				if a, b := triangles[k], triangles[NextHalfedge(k)]; a == u && b == v || a == v && b == u {
+					e = k
+					break
+				}
+			}
+			twin := halfedges[e]
+			if twin < 0 || used[twin/3] || stamp[twin/3] == tries {
+				return strip, tris
+			}
+			stamp[twin/3] = tries
+			e = twin
+			strip = append(strip, triangles[PrevHalfedge(twin)])
+			tris = append(tris, twin/3)
+		}
+	}
+
+	var strips [][]int
+	var best, bestTris, strip, tris []int
+	for _, bucket := range buckets {
+		for _, t := range bucket {
+			if used[t] {
+				continue
+			}
+			// try the three ways into the strip and keep the longest
+			best = best[:0]
+			for e := 3 * t; e < 3*t+3; e++ {
+				if strip, tris = walk(e, strip, tris); len(strip) > len(best) {
+					best, strip = strip, best
+					bestTris, tris = tris, bestTris
+				}
+			}
+			for _, u := range bestTris {
+				used[u] = true
+			}
+			strips = append(strips, append([]int(nil), best...))
+		}
+	}
+	return strips
+}
+
+// RestartStrips joins strips into one index buffer with restart between them, the primitive
+// restart index the renderer is set up with, such as 0xFFFF for 16-bit indices.
+func RestartStrips(strips [][]int, restart int) []int {
+	var indices []int
+	for i, strip := range strips {
+		if i > 0 {
+			indices = append(indices, restart)
+		}
+		indices = append(indices, strip...)
+	}
+	return indices
+}
+
+// StitchStrips joins strips into one index buffer for renderers without primitive restart by
+// repeating vertices between them, which adds triangles of zero area that are not drawn. A
+// strip that would start at an odd position gets an extra vertex so its winding is kept. Empty
+// strips are skipped.
+func StitchStrips(strips [][]int) []int {
+	var indices []int
+	for _, strip := range strips {
+		if len(strip) == 0 {
+			continue
+		}
+		if len(indices) > 0 {
+			indices = append(indices, indices[len(indices)-1], strip[0])
+			if len(indices)%2 != 0 {
+				indices = append(indices, strip[0])
+			}
+		}
+		indices = append(indices, strip...)
+	}
+	return indices
+}
+
+// StripTriangles turns an index buffer of strips, as made by RestartStrips or StitchStrips,
+// back into a list of triangles, leaving out those with a repeated vertex. Pass a negative
+// restart for buffers without restart indices.
+func StripTriangles(indices []int, restart int) []int {
+	var triangles []int
+	start := 0
+	for start < len(indices) {
+		end := start
+		for end < len(indices) && indices[end] != restart {
+			end++
+		}
+		for k := start; k+2 < end; k++ {
+			a, b, c := indices[k], indices[k+1], indices[k+2]
+			if a == b || b == c || a == c {
+				continue
+			}
+			if (k-start)%2 == 1 {
+				a, b = b, a
+			}
+			triangles = append(triangles, a, b, c)
+		}
+		start = end + 1
+	}
+	return triangles
+}
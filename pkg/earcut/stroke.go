@@ -0,0 +1,251 @@
+package earcut
+
+import (
+	"math"
+
+	"earcut-go/pkg/earcut/internal/arc"
+)
+
+// LineJoin is the shape drawn where two segments of a stroke meet.
+type LineJoin int
+
+const (
+	// MiterJoin extends the outer edges of the segments until they meet, falling back to
+	// BevelJoin when the point would stick out further than StrokeOptions.MiterLimit allows.
+	MiterJoin LineJoin = iota
+	// RoundJoin rounds the corner off with an arc around the vertex.
+	RoundJoin
+	// BevelJoin cuts the corner off straight between the outer edges.
+	BevelJoin
+)
+
+// LineCap is the shape drawn at the ends of an open stroke.
+type LineCap int
+
+const (
+	// ButtCap ends the stroke square at the end points.
+	ButtCap LineCap = iota
+	// RoundCap adds a half disc around each end point.
+	RoundCap
+	// SquareCap extends the stroke by half its width past each end point.
+	SquareCap
+)
+
+// StrokeOptions configures Stroke.
+type StrokeOptions struct {
+	// Width is the full width of the stroke.
+	Width float64
+	// Join and Cap shape the corners and the ends.
+	Join LineJoin
+	Cap  LineCap
+	// MiterLimit is the longest a miter may be, as a multiple of Width, measured from the inner
+	// to the outer corner; 0 means 4, as in SVG.
+	MiterLimit float64
+	// Tolerance is how far the chords of round joins and caps may stray from the arc; 0 means a
+	// hundredth of Width.
+	Tolerance float64
+	// Closed joins the last point back to the first instead of capping the ends.
+	Closed bool
+}
+
+// Stroke triangulates a polyline drawn with opts, so outlines such as roads and borders can be
+// meshed alongside the fills Earcut makes. points is a flat array with dim coordinates per
+// vertex, of which x and y are used. It returns flat x, y vertices and counter-clockwise
+// triangles indexing them, like Earcut's. Each segment is a quad and each join fills the gap on
+// the outside of the turn, so triangles overlap on the inside of turns; draw translucent strokes
+// with a stencil or depth test to avoid blending them twice.
+func Stroke(points []float64, dim int, opts StrokeOptions) (vertices []float64, triangles []int) {
+	if dim == 0 {
+		dim = 2
+	}
+	if opts.Width <= 0 || !(opts.Width < math.Inf(1)) {
+		return nil, nil
+	}
+	s := stroker{opts: opts, hw: opts.Width / 2}
+	if s.opts.MiterLimit <= 0 {
+		s.opts.MiterLimit = 4
+	}
+	if s.opts.Tolerance <= 0 {
+		s.opts.Tolerance = opts.Width / 100
+	}
+
+	// the polyline without repeated points, nor the closing one of a closed polyline
+	var pts [][2]float64
+	for i := 0; i+1 < len(points); i += dim {
+		p := [2]float64{points[i], points[i+1]}
+		if len(pts) == 0 || p != pts[len(pts)-1] {
+			pts = append(pts, p)
+		}
+	}
+	if opts.Closed && len(pts) > 1 && pts[0] == pts[len(pts)-1] {
+		pts = pts[:len(pts)-1]
+	}
+
+	switch {
+	case len(pts) == 0:
+		return nil, nil
+	case len(pts) == 1:
+		// a lone point only shows through its caps, back to back
+		if opts.Closed || opts.Cap == ButtCap {
+			return nil, nil
+		}
+		p := pts[0]
+		top, bottom := s.vertex(p[0], p[1]+s.hw), s.vertex(p[0], p[1]-s.hw)
+		s.cap(p, [2]float64{1, 0}, top, bottom)
+		s.cap(p, [2]float64{-1, 0}, bottom, top)
+		return s.vertices, s.triangles
+	}
+
+	segments := len(pts) - 1
+	if opts.Closed {
+		segments = len(pts)
+	}
+	// the left and right corners at the start and end of every segment
+	type quad struct {
+		dir            [2]float64
+		startL, startR int
+		endL, endR     int
+	}
+	quads := make([]quad, segments)
+	for i := range quads {
+		a, b := pts[i], pts[(i+1)%len(pts)]
+		d := unit(b[0]-a[0], b[1]-a[1])
+		nx, ny := -d[1]*s.hw, d[0]*s.hw
+		q := quad{
+			dir:    d,
+			startL: s.vertex(a[0]+nx, a[1]+ny), startR: s.vertex(a[0]-nx, a[1]-ny),
+			endL: s.vertex(b[0]+nx, b[1]+ny), endR: s.vertex(b[0]-nx, b[1]-ny),
+		}
+		s.triangle(q.startR, q.endR, q.endL)
+		s.triangle(q.startR, q.endL, q.startL)
+		quads[i] = q
+	}
+
+	for i := range pts {
+		prev, next := i-1, i
+		if opts.Closed {
+			prev = (i + segments - 1) % segments
+		}
+		if prev < 0 || next >= segments {
+			continue
+		}
+		s.join(pts[i], quads[prev].dir, quads[next].dir,
+			[2]int{quads[prev].endL, quads[prev].endR}, [2]int{quads[next].startL, quads[next].startR})
+	}
+	if !opts.Closed {
+		first, last := quads[0], quads[segments-1]
+		s.cap(pts[0], [2]float64{-first.dir[0], -first.dir[1]}, first.startR, first.startL)
+		s.cap(pts[len(pts)-1], last.dir, last.endL, last.endR)
+	}
+	return s.vertices, s.triangles
+}
+
+// stroker accumulates the mesh of one Stroke call
+type stroker struct {
+	opts StrokeOptions
+	// half the width
+	hw        float64
+	vertices  []float64
+	triangles []int
+}
+
+func (s *stroker) vertex(x, y float64) int {
+	s.vertices = append(s.vertices, x, y)
+	return len(s.vertices)/2 - 1
+}
+
+func (s *stroker) point(i int) [2]float64 {
+	return [2]float64{s.vertices[2*i], s.vertices[2*i+1]}
+}
+
+// add a triangle counter-clockwise, leaving out flat ones
+func (s *stroker) triangle(a, b, c int) {
+	pa, pb, pc := s.point(a), s.point(b), s.point(c)
+	switch area := (pb[0]-pa[0])*(pc[1]-pa[1]) - (pb[1]-pa[1])*(pc[0]-pa[0]); {
+	case area > 0:
+		s.triangles = append(s.triangles, a, b, c)
+	case area < 0:
+		s.triangles = append(s.triangles, a, c, b)
+	}
+}
+
+// fill the outside of the turn at p from the segment along d0, ending at corners from (left,
+// right), to the segment along d1, starting at corners to
+func (s *stroker) join(p, d0, d1 [2]float64, from, to [2]int) {
+	cross := d0[0]*d1[1] - d0[1]*d1[0]
+	dot := d0[0]*d1[0] + d0[1]*d1[1]
+	if cross == 0 && dot > 0 {
+		return
+	}
+	// a left turn has its outside on the right
+	outer, side := 1, -1.0
+	if cross < 0 {
+		outer, side = 0, 1
+	}
+	a, b := from[outer], to[outer]
+	centre := s.vertex(p[0], p[1])
+
+	switch s.opts.Join {
+	case MiterJoin:
+		// the miter point lies along the bisector of the two normals
+		n0 := [2]float64{-d0[1] * side, d0[0] * side}
+		n1 := [2]float64{-d1[1] * side, d1[0] * side}
+		m := unit(n0[0]+n1[0], n0[1]+n1[1])
+		if cos := m[0]*n0[0] + m[1]*n0[1]; cos > 0 && 1/cos <= s.opts.MiterLimit {
+			tip := s.vertex(p[0]+m[0]*s.hw/cos, p[1]+m[1]*s.hw/cos)
+			s.triangle(centre, a, tip)
+			s.triangle(centre, tip, b)
+			return
+		}
+	case RoundJoin:
+		s.fan(centre, a, b, -side)
+		return
+	}
+	s.triangle(centre, a, b)
+}
+
+// add a cap at end point p of a stroke leaving along d, between corners left and right as seen
+// looking along d
+func (s *stroker) cap(p, d [2]float64, left, right int) {
+	switch s.opts.Cap {
+	case RoundCap:
+		s.fan(s.vertex(p[0], p[1]), left, right, -1)
+	case SquareCap:
+		l, r := s.point(left), s.point(right)
+		dx, dy := d[0]*s.hw, d[1]*s.hw
+		farL, farR := s.vertex(l[0]+dx, l[1]+dy), s.vertex(r[0]+dx, r[1]+dy)
+		s.triangle(left, right, farR)
+		s.triangle(left, farR, farL)
+	}
+}
+
+// add a fan around centre sweeping the short way from corner a to corner b, or clockwise when
+// turn is -1 and counter-clockwise when it is 1 if they are opposite, in steps short enough to
+// stay within the tolerance
+func (s *stroker) fan(centre, a, b int, turn float64) {
+	c, pa, pb := s.point(centre), s.point(a), s.point(b)
+	start := math.Atan2(pa[1]-c[1], pa[0]-c[0])
+	// rounding could send a half circle either way
+	sweep := math.Remainder(math.Atan2(pb[1]-c[1], pb[0]-c[0])-start, 2*math.Pi)
+	if math.Abs(sweep) > math.Pi-1e-9 {
+		sweep = math.Pi * turn
+	}
+	n := arc.Segments(s.hw, sweep, s.opts.Tolerance)
+	last := a
+	for k := 1; k < n; k++ {
+		sin, cos := math.Sincos(start + sweep*float64(k)/float64(n))
+		v := s.vertex(c[0]+s.hw*cos, c[1]+s.hw*sin)
+		s.triangle(centre, last, v)
+		last = v
+	}
+	s.triangle(centre, last, b)
+}
+
+// the direction of (x, y) with length 1, or zero
+func unit(x, y float64) [2]float64 {
+	l := math.Hypot(x, y)
+	if l == 0 {
+		return [2]float64{}
+	}
+	return [2]float64{x / l, y / l}
+}
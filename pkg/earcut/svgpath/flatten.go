@@ -0,0 +1,163 @@
+package svgpath
+
+import (
+	"math"
+
+	"earcut-go/pkg/earcut/internal/arc"
+)
+
+// deepest subdivision of a cubic curve, 2^16 segments
+const maxDepth = 16
+
+// builder collects the rings of a path as its commands are read
+type builder struct {
+	tolerance float64
+	rings     [][]float64
+	// ring is the subpath being drawn, nil after a closepath until the next command
+	ring    []float64
+	started bool
+	// current point and start of the current subpath
+	x, y   float64
+	sx, sy float64
+	// the last control point of the previous command when it was a cubic ('C') or quadratic
+	// ('Q') curve, which smooth curves reflect
+	curve  byte
+	cx, cy float64
+}
+
+func (b *builder) moveTo(x, y float64) {
+	b.closePath()
+	b.started = true
+	b.x, b.y, b.sx, b.sy = x, y, x, y
+	b.ring = []float64{x, y}
+}
+
+// end the current subpath, returning to its start
+func (b *builder) closePath() {
+	b.curve = 0
+	if b.ring == nil {
+		return
+	}
+	ring := b.ring
+	if n := len(ring); n >= 4 && ring[0] == ring[n-2] && ring[1] == ring[n-1] {
+		ring = ring[:n-2]
+	}
+	if len(ring) >= 6 {
+		b.rings = append(b.rings, ring)
+	}
+	b.ring = nil
+	b.x, b.y = b.sx, b.sy
+}
+
+// add a point to the current subpath, starting a new one at the current point after a closepath
+func (b *builder) point(x, y float64) {
+	if b.ring == nil {
+		b.ring = []float64{b.x, b.y}
+	}
+	if n := len(b.ring); b.ring[n-2] != x || b.ring[n-1] != y {
+		b.ring = append(b.ring, x, y)
+	}
+	b.x, b.y = x, y
+}
+
+func (b *builder) lineTo(x, y float64) {
+	b.point(x, y)
+	b.curve = 0
+}
+
+// the first control point of a smooth curve: the reflection of the previous curve's last
+// control point when it was of the same kind, the current point otherwise
+func (b *builder) reflect(kind byte) (x, y float64) {
+	if b.curve != kind {
+		return b.x, b.y
+	}
+	return 2*b.x - b.cx, 2*b.y - b.cy
+}
+
+func (b *builder) cubicTo(x1, y1, x2, y2, x3, y3 float64) {
+	b.cubic(b.x, b.y, x1, y1, x2, y2, x3, y3, 0)
+	b.curve, b.cx, b.cy = 'C', x2, y2
+}
+
+// a quadratic curve is the cubic with its control point two thirds of the way along each side
+func (b *builder) quadTo(qx, qy, x, y float64) {
+	x0, y0 := b.x, b.y
+	b.cubic(x0, y0, x0+2*(qx-x0)/3, y0+2*(qy-y0)/3, x+2*(qx-x)/3, y+2*(qy-y)/3, x, y, 0)
+	b.curve, b.cx, b.cy = 'Q', qx, qy
+}
+
+// flatten a cubic curve by halving it until its control points, and so the whole curve, lie
+// within tolerance of the chord
+func (b *builder) cubic(x0, y0, x1, y1, x2, y2, x3, y3 float64, depth int) {
+	if depth == maxDepth || segmentDistance(x1, y1, x0, y0, x3, y3) <= b.tolerance &&
+		segmentDistance(x2, y2, x0, y0, x3, y3) <= b.tolerance {
+		b.point(x3, y3)
+		return
+	}
+	// de Casteljau at t = 1/2
+	ax, ay := (x0+x1)/2, (y0+y1)/2
+	bx, by := (x1+x2)/2, (y1+y2)/2
+	cx, cy := (x2+x3)/2, (y2+y3)/2
+	dx, dy := (ax+bx)/2, (ay+by)/2
+	ex, ey := (bx+cx)/2, (by+cy)/2
+	mx, my := (dx+ex)/2, (dy+ey)/2
+	b.cubic(x0, y0, ax, ay, dx, dy, mx, my, depth+1)
+	b.cubic(mx, my, ex, ey, cx, cy, x3, y3, depth+1)
+}
+
+// flatten an elliptical arc given in SVG's endpoint form, converting it to centre form as in
+// the SVG implementation notes, with steps small enough that each chord stays within tolerance
+func (b *builder) arcTo(rx, ry, rotation float64, large, sweep bool, x, y float64) {
+	x0, y0 := b.x, b.y
+	rx, ry = math.Abs(rx), math.Abs(ry)
+	b.curve = 0
+	if x0 == x && y0 == y {
+		return
+	}
+	if rx == 0 || ry == 0 {
+		b.point(x, y)
+		return
+	}
+
+	sin, cos := math.Sincos(rotation * math.Pi / 180)
+	// the start point in the ellipse's axes, relative to the chord's middle
+	hx, hy := (x0-x)/2, (y0-y)/2
+	px, py := cos*hx+sin*hy, -sin*hx+cos*hy
+	// scale radii too small to reach the end point
+	if l := px*px/(rx*rx) + py*py/(ry*ry); l > 1 {
+		rx, ry = rx*math.Sqrt(l), ry*math.Sqrt(l)
+	}
+	num := rx*rx*ry*ry - rx*rx*py*py - ry*ry*px*px
+	den := rx*rx*py*py + ry*ry*px*px
+	k := math.Sqrt(math.Max(0, num/den))
+	if large == sweep {
+		k = -k
+	}
+	ccx, ccy := k*rx*py/ry, -k*ry*px/rx
+	centerX, centerY := cos*ccx-sin*ccy+(x0+x)/2, sin*ccx+cos*ccy+(y0+y)/2
+
+	start := math.Atan2((py-ccy)/ry, (px-ccx)/rx)
+	delta := math.Atan2((-py-ccy)/ry, (-px-ccx)/rx) - start
+	if sweep && delta < 0 {
+		delta += 2 * math.Pi
+	} else if !sweep && delta > 0 {
+		delta -= 2 * math.Pi
+	}
+
+	n := arc.Segments(math.Max(rx, ry), delta, b.tolerance)
+	for i := 1; i < n; i++ {
+		s, c := math.Sincos(start + delta*float64(i)/float64(n))
+		b.point(centerX+rx*c*cos-ry*s*sin, centerY+rx*c*sin+ry*s*cos)
+	}
+	b.point(x, y)
+}
+
+// the distance from point p to the segment from a to b
+func segmentDistance(px, py, ax, ay, bx, by float64) float64 {
+	dx, dy := bx-ax, by-ay
+	t := 0.0
+	if l := dx*dx + dy*dy; l > 0 {
+		t = math.Max(0, math.Min(1, ((px-ax)*dx+(py-ay)*dy)/l))
+	}
+	return math.Hypot(px-ax-t*dx, py-ay-t*dy)
+}
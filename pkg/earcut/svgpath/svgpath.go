@@ -0,0 +1,225 @@
+// Package svgpath reads SVG path data, as found in the d attribute of a <path> element or in
+// glyph outlines, flattens its curves and arcs into straight segments and triangulates the area
+// it fills with earcut.
+package svgpath
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"earcut-go/pkg/earcut"
+)
+
+// ErrSyntax is returned for malformed path data.
+var ErrSyntax = errors.New("svgpath: syntax error")
+
+// DefaultTolerance is the tolerance used when none is given, a tenth of a unit, which is well
+// below a pixel for icons drawn at their own size.
+const DefaultTolerance = 0.1
+
+// Parse reads SVG path data and returns one flat ring of x, y pairs per subpath, with curves
+// and arcs replaced by segments that stay within tolerance of them; tolerance <= 0 means
+// DefaultTolerance. Coordinates are kept as they are, with y pointing down as in SVG. Every
+// subpath is closed, as filling does, and those with fewer than three distinct points are left
+// out.
+func Parse(d string, tolerance float64) ([][]float64, error) {
+	if tolerance <= 0 {
+		tolerance = DefaultTolerance
+	}
+	p := &parser{s: d}
+	b := &builder{tolerance: tolerance}
+
+	var cmd byte
+	for {
+		p.skipSpace()
+		if p.pos == len(p.s) {
+			break
+		}
+		if c := p.s[p.pos]; strings.IndexByte("MmLlHhVvCcSsQqTtAaZz", c) >= 0 {
+			cmd = c
+			p.pos++
+		} else if cmd == 0 || cmd == 'Z' || cmd == 'z' {
+			return nil, fmt.Errorf("%w: expected a command at offset %d", ErrSyntax, p.pos)
+		}
+		if cmd != 'M' && cmd != 'm' && !b.started {
+			return nil, fmt.Errorf("%w: path doesn't start with a moveto", ErrSyntax)
+		}
+
+		// relative commands are offset by the current point
+		ox, oy := 0.0, 0.0
+		if cmd >= 'a' {
+			ox, oy = b.x, b.y
+		}
+		var a [7]float64
+		var err error
+		switch cmd {
+		case 'M', 'm':
+			if err = p.args(a[:], "nn"); err == nil {
+				b.moveTo(ox+a[0], oy+a[1])
+				// further pairs are lines, L or l
+				cmd--
+			}
+		case 'L', 'l':
+			if err = p.args(a[:], "nn"); err == nil {
+				b.lineTo(ox+a[0], oy+a[1])
+			}
+		case 'H', 'h':
+			if err = p.args(a[:], "n"); err == nil {
+				b.lineTo(ox+a[0], b.y)
+			}
+		case 'V', 'v':
+			if err = p.args(a[:], "n"); err == nil {
+				b.lineTo(b.x, oy+a[0])
+			}
+		case 'C', 'c':
+			if err = p.args(a[:], "nnnnnn"); err == nil {
+				b.cubicTo(ox+a[0], oy+a[1], ox+a[2], oy+a[3], ox+a[4], oy+a[5])
+			}
+		case 'S', 's':
+			if err = p.args(a[:], "nnnn"); err == nil {
+				x1, y1 := b.reflect('C')
+				b.cubicTo(x1, y1, ox+a[0], oy+a[1], ox+a[2], oy+a[3])
+			}
+		case 'Q', 'q':
+			if err = p.args(a[:], "nnnn"); err == nil {
+				b.quadTo(ox+a[0], oy+a[1], ox+a[2], oy+a[3])
+			}
+		case 'T', 't':
+			if err = p.args(a[:], "nn"); err == nil {
+				x1, y1 := b.reflect('Q')
+				b.quadTo(x1, y1, ox+a[0], oy+a[1])
+			}
+		case 'A', 'a':
+			if err = p.args(a[:], "nnnffnn"); err == nil {
+				b.arcTo(a[0], a[1], a[2], a[3] != 0, a[4] != 0, ox+a[5], oy+a[6])
+			}
+		case 'Z', 'z':
+			b.closePath()
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	b.closePath()
+	return b.rings, nil
+}
+
+// Polygons parses d as Parse does and sorts the subpaths into polygons with holes under rule,
+// ready for earcut.Earcut.
+func Polygons(d string, tolerance float64, rule earcut.FillRule) ([]earcut.Polygon, error) {
+	rings, err := Parse(d, tolerance)
+	if err != nil {
+		return nil, err
+	}
+	groups, err := earcut.ClassifyRingsE(rings, 2, rule)
+	if err != nil {
+		return nil, err
+	}
+	var polygons []earcut.Polygon
+	for _, group := range groups {
+		p := earcut.Polygon{Dim: 2}
+		for k, r := range group {
+			if k > 0 {
+				p.Holes = append(p.Holes, len(p.Data)/2)
+			}
+			p.Data = append(p.Data, rings[r]...)
+		}
+		polygons = append(polygons, p)
+	}
+	return polygons, nil
+}
+
+// Triangulate parses d as Parse does and triangulates the area it fills under rule. It returns
+// the subpaths' rings joined into one vertex array and the triangles as indices into it.
+func Triangulate(d string, tolerance float64, rule earcut.FillRule) (vertices []float64, triangles []int, err error) {
+	rings, err := Parse(d, tolerance)
+	if err != nil {
+		return nil, nil, err
+	}
+	return earcut.EarcutRingsE(rings, 2, rule)
+}
+
+// parser is a hand-written scanner over path data
+type parser struct {
+	s   string
+	pos int
+}
+
+// skip white space and at most one comma
+func (p *parser) skipSpace() {
+	comma := false
+	for p.pos < len(p.s) {
+		switch c := p.s[p.pos]; {
+		case strings.IndexByte(" \t\r\n\f", c) >= 0:
+		case c == ',' && !comma:
+			comma = true
+		default:
+			return
+		}
+		p.pos++
+	}
+}
+
+// read the arguments of one command into a, n for a number and f for a flag
+func (p *parser) args(a []float64, kinds string) error {
+	for k := range kinds {
+		p.skipSpace()
+		if kinds[k] == 'f' {
+			// flags are a single digit and may run into what follows, as in "a1 1 0 011 1"
+			if p.pos == len(p.s) || p.s[p.pos] != '0' && p.s[p.pos] != '1' {
+				return fmt.Errorf("%w: expected a flag at offset %d", ErrSyntax, p.pos)
+			}
+			a[k] = float64(p.s[p.pos] - '0')
+			p.pos++
+			continue
+		}
+		v, err := p.number()
+		if err != nil {
+			return err
+		}
+		a[k] = v
+	}
+	return nil
+}
+
+// read a number, which ends where the next one starts, as in "1.5.5-2" for 1.5, .5 and -2
+func (p *parser) number() (float64, error) {
+	start := p.pos
+	digits := func() int {
+		from := p.pos
+		for p.pos < len(p.s) && p.s[p.pos] >= '0' && p.s[p.pos] <= '9' {
+			p.pos++
+		}
+		return p.pos - from
+	}
+	if p.pos < len(p.s) && (p.s[p.pos] == '+' || p.s[p.pos] == '-') {
+		p.pos++
+	}
+	n := digits()
+	if p.pos < len(p.s) && p.s[p.pos] == '.' {
+		p.pos++
+		n += digits()
+	}
+	if n == 0 {
+		p.pos = start
+		return 0, fmt.Errorf("%w: expected a number at offset %d", ErrSyntax, start)
+	}
+	// an exponent only when digits follow, so "2e" leaves the e alone
+	if p.pos < len(p.s) && (p.s[p.pos] == 'e' || p.s[p.pos] == 'E') {
+		mark := p.pos
+		p.pos++
+		if p.pos < len(p.s) && (p.s[p.pos] == '+' || p.s[p.pos] == '-') {
+			p.pos++
+		}
+		if digits() == 0 {
+			p.pos = mark
+		}
+	}
+	v, err := strconv.ParseFloat(p.s[start:p.pos], 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: bad number %q at offset %d", ErrSyntax, p.s[start:p.pos], start)
+	}
+	return v, nil
+}
@@ -0,0 +1,76 @@
+package earcut
+
+// Triangulator triangulates polygons like Earcut but keeps its node storage and scratch
+// buffers between calls, so triangulating many polygons barely allocates.
+// The zero value is ready to use. A Triangulator must not be used concurrently.
+type Triangulator struct {
+	// Options applies to every call to Triangulate.
+	Options Options
+
+	e earcutter
+	// indices computed by TriangulateT before conversion
+	scratch []int
+}
+
+// Triangulate appends the triangle indices of the polygon described by data, holes and dim
+// (see Earcut) to dst and returns the extended slice. Like EarcutWithOptions, it ignores
+// Options.MaxWork; TriangulateContext and TriangulateT apply it.
+func (t *Triangulator) Triangulate(dst []int, data []float64, holes []int, dim int) []int {
+	t.e.opts = t.Options
+	t.e.opts.MaxWork = 0
+	return run(&t.e, dst, data, holes, dim)
+}
+
+// minimum number of nodes allocated at once
+const minNodeChunk = 64
+
+// nodePool hands out nodes from a few large chunks instead of allocating them one by one;
+// reset makes every node available again without freeing the chunks
+type nodePool struct {
+	chunks [][]Node
+	// chunk and offset of the next free node
+	chunk, used int
+}
+
+// make every node available again and ensure room for at least n nodes without growing
+func (p *nodePool) reset(n int) {
+	p.chunk = 0
+	p.used = 0
+
+	capacity := 0
+	for _, c := range p.chunks {
+		capacity += len(c)
+	}
+	if capacity < n {
+		// leave some slack for the nodes added when the polygon gets split
+		p.grow(n - capacity + n/8 + 4)
+	}
+}
+
+// take a zeroed node from the pool, doubling its capacity when full
+func (p *nodePool) get() *Node {
+	for p.chunk < len(p.chunks) && p.used == len(p.chunks[p.chunk]) {
+		p.chunk++
+		p.used = 0
+	}
+	if p.chunk == len(p.chunks) {
+		capacity := 0
+		for _, c := range p.chunks {
+			capacity += len(c)
+		}
+		if capacity < minNodeChunk {
+			capacity = minNodeChunk
+		}
+		p.grow(capacity)
+	}
+
+	node := &p.chunks[p.chunk][p.used]
+	p.used++
+	*node = Node{}
+	return node
+}
+
+// add a chunk of n nodes
+func (p *nodePool) grow(n int) {
+	p.chunks = append(p.chunks, make([]Node, n))
+}
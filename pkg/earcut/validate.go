@@ -0,0 +1,355 @@
+package earcut
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// ProblemKind tells what Validate found wrong with a polygon.
+type ProblemKind int
+
+const (
+	// ZeroArea means a ring has fewer than three distinct vertices or encloses no area.
+	ZeroArea ProblemKind = iota
+	// DuplicateVertex means a vertex repeats the one before it, or the last repeats the first.
+	DuplicateVertex
+	// Spike means a ring doubles back on itself along an edge; Vertex is the tip.
+	Spike
+	// SelfIntersection means two edges of the same ring cross or touch.
+	SelfIntersection
+	// WrongWinding means the outer ring is not counter-clockwise or a hole is not clockwise,
+	// as GeoJSON requires. Earcut itself accepts either winding.
+	WrongWinding
+	// HoleCrossesShell means a hole crosses the outer ring or overlaps one of its edges.
+	HoleCrossesShell
+	// HoleOutsideShell means a hole lies outside the outer ring.
+	HoleOutsideShell
+	// HolesOverlap means two holes cross, overlap along an edge, or one lies inside the other.
+	HolesOverlap
+)
+
+var problemNames = [...]string{
+	ZeroArea:         "zero area",
+	DuplicateVertex:  "duplicate vertex",
+	Spike:            "spike",
+	SelfIntersection: "self-intersection",
+	WrongWinding:     "wrong winding",
+	HoleCrossesShell: "hole crosses shell",
+	HoleOutsideShell: "hole outside shell",
+	HolesOverlap:     "holes overlap",
+}
+
+func (k ProblemKind) String() string {
+	if k >= 0 && int(k) < len(problemNames) {
+		return problemNames[k]
+	}
+	return fmt.Sprintf("ProblemKind(%d)", int(k))
+}
+
+// Problem is a defect found by Validate.
+type Problem struct {
+	Kind ProblemKind
+	// Ring is the ring the problem is in: 0 for the outer ring and i+1 for hole i.
+	Ring int
+	// Other is the second ring of a problem between two rings, or -1.
+	Other int
+	// Vertex is the vertex, or the first vertex of the edge, where the problem is, or -1 when
+	// it concerns the whole ring.
+	Vertex int
+	// X and Y locate the problem; for a whole ring they are its first vertex, or 0 if it has none.
+	X, Y float64
+}
+
+func (p Problem) String() string {
+	s := fmt.Sprintf("%v in ring %d", p.Kind, p.Ring)
+	if p.Other >= 0 {
+		s += fmt.Sprintf(" and ring %d", p.Other)
+	}
+	if p.Vertex >= 0 {
+		s += fmt.Sprintf(" at vertex %d", p.Vertex)
+	}
+	return s + fmt.Sprintf(" (%v, %v)", p.X, p.Y)
+}
+
+// Validate checks the polygon described by data, holeIndices and dim (see Earcut) for the
+// defects Earcut silently works around, usually at the cost of a high Deviation, and returns
+// them in ring order. Rings may touch each other at single points. Use EarcutE to check the
+// arguments themselves first; only x and y are looked at.
+func Validate(data []float64, holeIndices []int, dim int) []Problem {
+	if dim == 0 {
+		dim = 2
+	}
+	rings := splitRings(len(data)/dim, holeIndices)
+	var problems []Problem
+	valid := make([]bool, len(rings))
+
+	var edges []ringEdge
+	for r, bounds := range rings {
+		first := Problem{Ring: r, Other: -1, Vertex: -1}
+		if bounds[0] >= bounds[1] {
+			first.Kind = ZeroArea
+			problems = append(problems, first)
+			continue
+		}
+		first.X, first.Y = data[bounds[0]*dim], data[bounds[0]*dim+1]
+		for i := bounds[0]; i < bounds[1]; i++ {
+			j := i + 1
+			if j == bounds[1] {
+				j = bounds[0]
+			}
+			if i != j && data[i*dim] == data[j*dim] && data[i*dim+1] == data[j*dim+1] {
+				// report the later of the two
+				dup := j
+				if j == bounds[0] {
+					dup = i
+				}
+				problems = append(problems, Problem{Kind: DuplicateVertex, Ring: r, Other: -1, Vertex: dup,
+					X: data[dup*dim], Y: data[dup*dim+1]})
+			}
+		}
+
+		// the rest of the checks look at the ring without its spikes
+		var spikes []Problem
+		kept := simplifyRing(data, dim, bounds[0], bounds[1], func(tip int) {
+			spikes = append(spikes, Problem{Kind: Spike, Ring: r, Other: -1, Vertex: tip,
+				X: data[tip*dim], Y: data[tip*dim+1]})
+		})
+		area := 0.0
+		if len(kept) >= 3 {
+			area = robustSignedArea(gather(data, dim, kept), 0, len(kept)*dim, dim)
+		}
+		if area == 0 {
+			// a ring that is nothing but spikes has just this one problem
+			first.Kind = ZeroArea
+			problems = append(problems, first)
+			continue
+		}
+		problems = append(problems, spikes...)
+		valid[r] = true
+		if (r == 0) != (area > 0) {
+			first.Kind = WrongWinding
+			problems = append(problems, first)
+		}
+		for k, i := range kept {
+			edges = append(edges, ringEdge{ring: r, k: k, n: len(kept), a: i, b: kept[(k+1)%len(kept)]})
+		}
+	}
+
+	// edges can only meet if their x ranges overlap
+	crossed := map[[2]int]bool{}
+	seen := map[Problem]bool{}
+	overlappingEdges(data, dim, edges, func(e, f ringEdge) {
+		kind, x, y := segmentContact(data, dim, e.a, e.b, f.a, f.b)
+		if kind == apart {
+			return
+		}
+		p := Problem{Ring: e.ring, Other: f.ring, Vertex: e.a, X: x, Y: y}
+		switch {
+		case e.ring == f.ring && e.adjacent(f):
+			// adjacent edges share a vertex, and can't overlap now the spikes are gone
+			return
+		case e.ring == f.ring:
+			p.Kind, p.Other = SelfIntersection, -1
+		case kind == touch:
+			return
+		case e.ring == 0:
+			p.Kind = HoleCrossesShell
+		default:
+			p.Kind = HolesOverlap
+		}
+		if p.Other >= 0 {
+			crossed[[2]int{p.Ring, p.Other}] = true
+		}
+		if !seen[p] {
+			seen[p] = true
+			problems = append(problems, p)
+		}
+	})
+
+	// holes that cross nothing may still lie outside the shell or inside another hole
+	ring := func(r int) []float64 { return data[rings[r][0]*dim : rings[r][1]*dim] }
+	for h := 1; h < len(rings); h++ {
+		if !valid[h] {
+			continue
+		}
+		for r := 0; r < len(rings); r++ {
+			if r == h || !valid[r] || crossed[[2]int{r, h}] || crossed[[2]int{h, r}] {
+				continue
+			}
+			in, out := ringInRing(ring(h), ring(r), dim)
+			switch {
+			case r == 0 && out >= 0:
+				problems = append(problems, Problem{Kind: HoleOutsideShell, Ring: h, Other: 0, Vertex: rings[h][0] + out/dim,
+					X: ring(h)[out], Y: ring(h)[out+1]})
+			case r > 0 && in >= 0:
+				problems = append(problems, Problem{Kind: HolesOverlap, Ring: h, Other: r, Vertex: rings[h][0] + in/dim,
+					X: ring(h)[in], Y: ring(h)[in+1]})
+			}
+		}
+	}
+
+	sort.SliceStable(problems, func(i, j int) bool {
+		p, q := problems[i], problems[j]
+		if p.Ring != q.Ring {
+			return p.Ring < q.Ring
+		}
+		if p.Vertex != q.Vertex {
+			return p.Vertex < q.Vertex
+		}
+		if p.Y != q.Y {
+			return p.Y < q.Y
+		}
+		return p.X < q.X
+	})
+	return problems
+}
+
+// the first and past-the-last vertex of every ring
+func splitRings(n int, holeIndices []int) [][2]int {
+	starts := append([]int{0}, holeIndices...)
+	rings := make([][2]int, len(starts))
+	for r, start := range starts {
+		end := n
+		if r+1 < len(starts) {
+			end = starts[r+1]
+		}
+		rings[r] = [2]int{start, end}
+	}
+	return rings
+}
+
+// ringEdge is the edge from vertex a to b, the k-th of the n edges left of its ring once
+// duplicate vertices are skipped
+type ringEdge struct {
+	ring, k, n int
+	a, b       int
+}
+
+func (e ringEdge) adjacent(f ringEdge) bool {
+	d := e.k - f.k
+	return d == 1 || d == -1 || d == e.n-1 || d == 1-e.n
+}
+
+// call visit with every pair of edges whose x ranges overlap, found by sweeping them left to
+// right; the edge of the lower ring, or else of the lower vertex, comes first
+func overlappingEdges(data []float64, dim int, edges []ringEdge, visit func(e, f ringEdge)) {
+	minX := func(e ringEdge) float64 { return math.Min(data[e.a*dim], data[e.b*dim]) }
+	maxX := func(e ringEdge) float64 { return math.Max(data[e.a*dim], data[e.b*dim]) }
+	sort.Slice(edges, func(i, j int) bool { return minX(edges[i]) < minX(edges[j]) })
+
+	var active []ringEdge
+	for _, e := range edges {
+		kept := active[:0]
+		for _, f := range active {
+			if maxX(f) >= minX(e) {
+				kept = append(kept, f)
+			}
+		}
+		active = kept
+		for _, f := range active {
+			lo, hi := f, e
+			if hi.ring < lo.ring || hi.ring == lo.ring && hi.a < lo.a {
+				lo, hi = hi, lo
+			}
+			visit(lo, hi)
+		}
+		active = append(active, e)
+	}
+}
+
+// how two segments meet
+type contact int
+
+const (
+	apart contact = iota
+	// at a single point that is an endpoint of at least one of them
+	touch
+	// at a point inside both, or along a stretch when they are collinear
+	cross
+)
+
+// how the segments from vertex a to b and from c to d meet, and where
+func segmentContact(data []float64, dim, a, b, c, d int) (contact, float64, float64) {
+	ax, ay, bx, by := data[a*dim], data[a*dim+1], data[b*dim], data[b*dim+1]
+	cx, cy, dx, dy := data[c*dim], data[c*dim+1], data[d*dim], data[d*dim+1]
+	o1 := Orient2D(ax, ay, bx, by, cx, cy)
+	o2 := Orient2D(ax, ay, bx, by, dx, dy)
+	o3 := Orient2D(cx, cy, dx, dy, ax, ay)
+	o4 := Orient2D(cx, cy, dx, dy, bx, by)
+
+	if o1 == 0 && o2 == 0 {
+		// collinear: compare the stretches along the axis they extend most in
+		pa, pb, pc, pd := ax, bx, cx, dx
+		if math.Abs(bx-ax) < math.Abs(by-ay) {
+			pa, pb, pc, pd = ay, by, cy, dy
+		}
+		lo := math.Max(math.Min(pa, pb), math.Min(pc, pd))
+		hi := math.Min(math.Max(pa, pb), math.Max(pc, pd))
+		if lo > hi {
+			return apart, 0, 0
+		}
+		kind := touch
+		if lo < hi {
+			kind = cross
+		}
+		for _, p := range [][3]float64{{pa, ax, ay}, {pb, bx, by}, {pc, cx, cy}, {pd, dx, dy}} {
+			if p[0] == lo {
+				return kind, p[1], p[2]
+			}
+		}
+	}
+	if sign(o1)*sign(o2) > 0 || sign(o3)*sign(o4) > 0 {
+		return apart, 0, 0
+	}
+	switch {
+	case o1 == 0:
+		return touch, cx, cy
+	case o2 == 0:
+		return touch, dx, dy
+	case o3 == 0:
+		return touch, ax, ay
+	case o4 == 0:
+		return touch, bx, by
+	}
+	t := o3 / (o3 - o4)
+	return cross, ax + t*(bx-ax), ay + t*(by-ay)
+}
+
+// the first vertex of flat ring inner inside flat ring outer and the first outside it, as
+// offsets into inner, or -1 if there is none
+func ringInRing(inner, outer []float64, dim int) (in, out int) {
+	in, out = -1, -1
+	for i := 0; i < len(inner) && (in < 0 || out < 0); i += dim {
+		switch where := pointInRing(outer, dim, inner[i], inner[i+1]); {
+		case where > 0 && in < 0:
+			in = i
+		case where < 0 && out < 0:
+			out = i
+		}
+	}
+	return in, out
+}
+
+// whether (x, y) lies inside the flat ring: 1 inside, -1 outside and 0 on its outline
+func pointInRing(ring []float64, dim int, x, y float64) int {
+	inside := false
+	for i, j := 0, len(ring)-dim; i < len(ring); j, i = i, i+dim {
+		xi, yi, xj, yj := ring[i], ring[i+1], ring[j], ring[j+1]
+		if Orient2D(xj, yj, xi, yi, x, y) == 0 &&
+			math.Min(xi, xj) <= x && x <= math.Max(xi, xj) && math.Min(yi, yj) <= y && y <= math.Max(yi, yj) {
+			return 0
+		}
+		if (yi > y) != (yj > y) {
+			// the side of the edge the point is on, made exact, says whether the ray crosses it
+			o := Orient2D(xj, yj, xi, yi, x, y)
+			if (yi > yj) == (o > 0) {
+				inside = !inside
+			}
+		}
+	}
+	if inside {
+		return 1
+	}
+	return -1
+}
@@ -0,0 +1,251 @@
+package wkt
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"strings"
+
+	"earcut-go/pkg/earcut"
+)
+
+// WKB geometry type codes
+const (
+	wkbPolygon            = 3
+	wkbMultiPolygon       = 6
+	wkbGeometryCollection = 7
+	wkbTIN                = 16
+	wkbTriangle           = 17
+)
+
+// EWKB flags in the high bits of the geometry type
+const (
+	ewkbZ    = 0x80000000
+	ewkbM    = 0x40000000
+	ewkbSRID = 0x20000000
+)
+
+// ParseWKB reads a POLYGON, MULTIPOLYGON, TRIANGLE, TIN or a GEOMETRYCOLLECTION of them in ISO
+// WKB or PostGIS EWKB.
+func ParseWKB(b []byte) (*Geometry, error) {
+	r := &wkbReader{b: b}
+	g := &Geometry{}
+	if err := r.geometry(g, true); err != nil {
+		return nil, err
+	}
+	if r.off != len(b) {
+		return nil, fmt.Errorf("%w: %d trailing bytes", ErrSyntax, len(b)-r.off)
+	}
+	return g, nil
+}
+
+// ParseHex reads hex-encoded WKB or EWKB, as printed by PostGIS.
+func ParseHex(s string) (*Geometry, error) {
+	b, err := hex.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrSyntax, err)
+	}
+	return ParseWKB(b)
+}
+
+type wkbReader struct {
+	b     []byte
+	off   int
+	order binary.ByteOrder
+}
+
+func (r *wkbReader) uint32() (uint32, error) {
+	if len(r.b)-r.off < 4 {
+		return 0, ErrTruncated
+	}
+	v := r.order.Uint32(r.b[r.off:])
+	r.off += 4
+	return v, nil
+}
+
+func (r *wkbReader) float64() (float64, error) {
+	if len(r.b)-r.off < 8 {
+		return 0, ErrTruncated
+	}
+	v := math.Float64frombits(r.order.Uint64(r.b[r.off:]))
+	r.off += 8
+	return v, nil
+}
+
+// read the byte order and type of a geometry; top is false for the members of a MULTIPOLYGON,
+// TIN or GEOMETRYCOLLECTION
+func (r *wkbReader) header(g *Geometry, top bool) (uint32, error) {
+	if r.off >= len(r.b) {
+		return 0, ErrTruncated
+	}
+	switch r.b[r.off] {
+	case 0:
+		r.order = binary.BigEndian
+	case 1:
+		r.order = binary.LittleEndian
+	default:
+		return 0, fmt.Errorf("%w: bad byte order %d", ErrSyntax, r.b[r.off])
+	}
+	r.off++
+
+	t, err := r.uint32()
+	if err != nil {
+		return 0, err
+	}
+
+	hasZ, hasM := t&ewkbZ != 0, t&ewkbM != 0
+	if t&ewkbSRID != 0 {
+		srid, err := r.uint32()
+		if err != nil {
+			return 0, err
+		}
+		g.SRID = int(srid)
+	}
+	t &^= ewkbZ | ewkbM | ewkbSRID
+
+	// ISO WKB adds 1000 for Z, 2000 for M and 3000 for ZM
+	switch t / 1000 {
+	case 1:
+		hasZ = true
+	case 2:
+		hasM = true
+	case 3:
+		hasZ, hasM = true, true
+	}
+	layout := XY
+	switch {
+	case hasZ && hasM:
+		layout = XYZM
+	case hasZ:
+		layout = XYZ
+	case hasM:
+		layout = XYM
+	}
+	if top {
+		g.Layout = layout
+	} else if layout != g.Layout {
+		return 0, fmt.Errorf("%w: mixed dimensions in a collection", ErrSyntax)
+	}
+	return t % 1000, nil
+}
+
+func (r *wkbReader) geometry(g *Geometry, top bool) error {
+	t, err := r.header(g, top)
+	if err != nil {
+		return err
+	}
+	switch t {
+	case wkbPolygon, wkbTriangle:
+		return r.polygon(g)
+	case wkbMultiPolygon, wkbTIN, wkbGeometryCollection:
+		n, err := r.uint32()
+		if err != nil {
+			return err
+		}
+		for i := uint32(0); i < n; i++ {
+			if err := r.member(g, t); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("%w: WKB type %d", ErrUnsupportedType, t)
+}
+
+// read a member of a collection of the given type: polygons in a MULTIPOLYGON, triangles in a
+// TIN, and any of these in a GEOMETRYCOLLECTION
+func (r *wkbReader) member(g *Geometry, collection uint32) error {
+	start := r.off
+	t, err := r.header(g, false)
+	if err != nil {
+		return err
+	}
+	switch {
+	case collection == wkbMultiPolygon && t != wkbPolygon,
+		collection == wkbTIN && t != wkbTriangle:
+		return fmt.Errorf("%w: WKB type %d in a collection of type %d", ErrSyntax, t, collection)
+	}
+	r.off = start
+	return r.geometry(g, false)
+}
+
+func (r *wkbReader) polygon(g *Geometry) error {
+	dim := g.Layout.Dim()
+	rings, err := r.uint32()
+	if err != nil {
+		return err
+	}
+
+	poly := earcut.Polygon{Dim: dim}
+	emptyShell := false
+	for i := uint32(0); i < rings; i++ {
+		n, err := r.uint32()
+		if err != nil {
+			return err
+		}
+		// guard the allocation below against garbage counts
+		if uint64(n)*uint64(dim)*8 > uint64(len(r.b)-r.off) {
+			return ErrTruncated
+		}
+		ringStart := len(poly.Data)
+		for j := 0; j < int(n)*dim; j++ {
+			v, err := r.float64()
+			if err != nil {
+				return err
+			}
+			poly.Data = append(poly.Data, v)
+		}
+		poly.Data = closeRing(poly.Data, ringStart, dim)
+		if i == 0 {
+			emptyShell = len(poly.Data) == 0
+		} else if len(poly.Data) > ringStart {
+			poly.Holes = append(poly.Holes, ringStart/dim)
+		}
+	}
+
+	// an empty exterior makes the polygon empty, whatever its holes
+	if rings > 0 && !emptyShell {
+		g.Polygons = append(g.Polygons, poly)
+	}
+	return nil
+}
+
+// MarshalWKB writes triangles over data (layout.Dim() coordinates per vertex) as ISO WKB in the
+// given form and byte order.
+func MarshalWKB(form Form, layout Layout, data []float64, triangles []int, order binary.AppendByteOrder) []byte {
+	dim := layout.Dim()
+	count := len(triangles) / 3
+	// ISO type codes add 1000 per Layout value
+	offset := uint32(layout) * 1000
+
+	b := make([]byte, 0, 9+count*(13+4*dim*8))
+	header := func(t uint32) {
+		if order == binary.BigEndian {
+			b = append(b, 0)
+		} else {
+			b = append(b, 1)
+		}
+		b = order.AppendUint32(b, t)
+	}
+
+	if form == TIN {
+		header(wkbTIN + offset)
+	} else {
+		// a GEOMETRYCOLLECTION carries the dimension of its members
+		header(wkbGeometryCollection + offset)
+	}
+	b = order.AppendUint32(b, uint32(count))
+
+	for t := 0; t < count*3; t += 3 {
+		header(wkbTriangle + offset)
+		b = order.AppendUint32(b, 1) // one ring
+		b = order.AppendUint32(b, 4) // closed, so four points
+		for _, i := range [4]int{triangles[t], triangles[t+1], triangles[t+2], triangles[t]} {
+			for c := 0; c < dim; c++ {
+				b = order.AppendUint64(b, math.Float64bits(data[i*dim+c]))
+			}
+		}
+	}
+	return b
+}
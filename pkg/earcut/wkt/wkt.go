@@ -0,0 +1,401 @@
+// Package wkt reads polygons from Well-Known Text and Well-Known Binary (including the PostGIS
+// EWKT/EWKB extensions) into the form Earcut accepts, and writes triangulations back as a TIN or
+// a GEOMETRYCOLLECTION of TRIANGLEs, which it reads too.
+package wkt
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"earcut-go/pkg/earcut"
+)
+
+// Errors returned by the parsers.
+var (
+	// ErrSyntax is returned for malformed WKT.
+	ErrSyntax = errors.New("wkt: syntax error")
+	// ErrUnsupportedType is returned for geometry types without area, such as POINT.
+	ErrUnsupportedType = errors.New("wkt: unsupported geometry type")
+	// ErrTruncated is returned when WKB ends in the middle of a geometry.
+	ErrTruncated = errors.New("wkt: truncated WKB")
+)
+
+// Layout describes which coordinates each vertex carries. The values match the thousands digit
+// of ISO WKB type codes.
+type Layout int
+
+const (
+	XY Layout = iota
+	XYZ
+	XYM
+	XYZM
+)
+
+// Dim returns the number of coordinates per vertex.
+func (l Layout) Dim() int {
+	switch l {
+	case XYZ, XYM:
+		return 3
+	case XYZM:
+		return 4
+	}
+	return 2
+}
+
+// tag returns the WKT dimension keyword, with a leading space
+func (l Layout) tag() string {
+	switch l {
+	case XYZ:
+		return " Z"
+	case XYM:
+		return " M"
+	case XYZM:
+		return " ZM"
+	}
+	return ""
+}
+
+// Geometry is a parsed POLYGON, MULTIPOLYGON, TRIANGLE, TIN or GEOMETRYCOLLECTION of them.
+type Geometry struct {
+	// Layout applies to every polygon.
+	Layout Layout
+	// SRID is the spatial reference from EWKT or EWKB, 0 if absent.
+	SRID int
+	// Polygons holds one entry per polygon, rings without their closing duplicate vertex.
+	Polygons []earcut.Polygon
+}
+
+// Triangulate runs Earcut on every polygon and returns their vertices concatenated into one
+// array along with triangle indices into it.
+func (g *Geometry) Triangulate() (data []float64, triangles []int) {
+	var tr earcut.Triangulator
+	for _, p := range g.Polygons {
+		offset := len(data) / g.Layout.Dim()
+		data = append(data, p.Data...)
+		start := len(triangles)
+		triangles = tr.Triangulate(triangles, p.Data, p.Holes, p.Dim)
+		for i := start; i < len(triangles); i++ {
+			triangles[i] += offset
+		}
+	}
+	return data, triangles
+}
+
+// Parse reads a POLYGON, MULTIPOLYGON, TRIANGLE, TIN or a GEOMETRYCOLLECTION of them in WKT,
+// optionally with Z, M or ZM and an EWKT "SRID=...;" prefix. Positions with three or four
+// coordinates but no dimension keyword are read as XYZ and XYZM.
+func Parse(s string) (*Geometry, error) {
+	p := &parser{s: s}
+	g := &Geometry{}
+
+	if rest, ok := strings.CutPrefix(strings.TrimSpace(s), "SRID="); ok {
+		srid, body, found := strings.Cut(rest, ";")
+		if !found {
+			return nil, fmt.Errorf("%w: missing ';' after SRID", ErrSyntax)
+		}
+		id, err := strconv.Atoi(strings.TrimSpace(srid))
+		if err != nil {
+			return nil, fmt.Errorf("%w: bad SRID %q", ErrSyntax, srid)
+		}
+		g.SRID = id
+		p.s = body
+	}
+
+	if err := p.geometry(g, true); err != nil {
+		return nil, err
+	}
+
+	p.skipSpace()
+	if p.pos != len(p.s) {
+		return nil, fmt.Errorf("%w: unexpected %q at offset %d", ErrSyntax, p.s[p.pos:], p.pos)
+	}
+	g.Layout = p.layout
+	return g, nil
+}
+
+// read a tagged geometry and add its polygons to g; top is false for the members of a
+// GEOMETRYCOLLECTION, whose dimension must agree with the collection's
+func (p *parser) geometry(g *Geometry, top bool) error {
+	kind := strings.ToUpper(p.word())
+	layout, explicit := XY, true
+	switch strings.ToUpper(p.peekWord()) {
+	case "Z":
+		layout = XYZ
+	case "M":
+		layout = XYM
+	case "ZM":
+		layout = XYZM
+	default:
+		explicit = false
+	}
+	if explicit {
+		p.word()
+	}
+	if top {
+		p.layout, p.explicit = layout, explicit
+	} else if explicit {
+		if p.explicit && layout != p.layout {
+			return fmt.Errorf("%w: mixed dimensions in GEOMETRYCOLLECTION", ErrSyntax)
+		}
+		p.layout, p.explicit = layout, true
+	}
+
+	switch kind {
+	case "POLYGON", "TRIANGLE":
+		return p.polygon(g)
+	case "MULTIPOLYGON", "TIN":
+		return p.list(func() error { return p.polygon(g) })
+	case "GEOMETRYCOLLECTION":
+		return p.list(func() error { return p.geometry(g, false) })
+	case "":
+		return fmt.Errorf("%w: missing geometry type", ErrSyntax)
+	}
+	return fmt.Errorf("%w: %s", ErrUnsupportedType, kind)
+}
+
+// read EMPTY or a parenthesised, comma-separated list of items
+func (p *parser) list(item func() error) error {
+	if p.empty() {
+		return nil
+	}
+	if err := p.expect('('); err != nil {
+		return err
+	}
+	for {
+		if err := item(); err != nil {
+			return err
+		}
+		if !p.accept(',') {
+			break
+		}
+	}
+	return p.expect(')')
+}
+
+// parser is a hand-written scanner over a WKT string
+type parser struct {
+	s   string
+	pos int
+	// layout of the geometry and whether it was given by a keyword rather than inferred
+	layout   Layout
+	explicit bool
+}
+
+func (p *parser) skipSpace() {
+	for p.pos < len(p.s) && strings.IndexByte(" \t\r\n", p.s[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+// read the next run of letters
+func (p *parser) word() string {
+	p.skipSpace()
+	start := p.pos
+	for p.pos < len(p.s) && isLetter(p.s[p.pos]) {
+		p.pos++
+	}
+	return p.s[start:p.pos]
+}
+
+func (p *parser) peekWord() string {
+	pos := p.pos
+	w := p.word()
+	p.pos = pos
+	return w
+}
+
+func isLetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// consume c if it is the next character
+func (p *parser) accept(c byte) bool {
+	p.skipSpace()
+	if p.pos < len(p.s) && p.s[p.pos] == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(c byte) error {
+	if !p.accept(c) {
+		return fmt.Errorf("%w: expected '%c' at offset %d", ErrSyntax, c, p.pos)
+	}
+	return nil
+}
+
+// consume the EMPTY keyword if it is next
+func (p *parser) empty() bool {
+	if strings.EqualFold(p.peekWord(), "EMPTY") {
+		p.word()
+		return true
+	}
+	return false
+}
+
+func (p *parser) number() (float64, error) {
+	p.skipSpace()
+	start := p.pos
+	for p.pos < len(p.s) && strings.IndexByte("+-.0123456789eE", p.s[p.pos]) >= 0 {
+		p.pos++
+	}
+	v, err := strconv.ParseFloat(p.s[start:p.pos], 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: bad number %q at offset %d", ErrSyntax, p.s[start:p.pos], start)
+	}
+	return v, nil
+}
+
+// read a position, appending its coordinates to data; the first position of a geometry
+// without a dimension keyword decides its layout
+func (p *parser) position(data []float64) ([]float64, error) {
+	n := 0
+	for {
+		v, err := p.number()
+		if err != nil {
+			return data, err
+		}
+		data = append(data, v)
+		n++
+		p.skipSpace()
+		if p.pos == len(p.s) || p.s[p.pos] == ',' || p.s[p.pos] == ')' {
+			break
+		}
+	}
+
+	if !p.explicit {
+		switch n {
+		case 2:
+			p.layout = XY
+		case 3:
+			p.layout = XYZ
+		case 4:
+			p.layout = XYZM
+		}
+		p.explicit = true
+	}
+	if n != p.layout.Dim() {
+		return data, fmt.Errorf("%w: position with %d coordinates in %d-dimensional geometry", ErrSyntax, n, p.layout.Dim())
+	}
+	return data, nil
+}
+
+// read a polygon body (or EMPTY) and add it to g
+func (p *parser) polygon(g *Geometry) error {
+	if p.empty() {
+		return nil
+	}
+	if err := p.expect('('); err != nil {
+		return err
+	}
+
+	poly := earcut.Polygon{}
+	emptyShell := false
+	for ring := 0; ; ring++ {
+		ringStart := len(poly.Data)
+		if !p.empty() {
+			if err := p.expect('('); err != nil {
+				return err
+			}
+			for {
+				var err error
+				if poly.Data, err = p.position(poly.Data); err != nil {
+					return err
+				}
+				if !p.accept(',') {
+					break
+				}
+			}
+			if err := p.expect(')'); err != nil {
+				return err
+			}
+		}
+		poly.Data = closeRing(poly.Data, ringStart, p.layout.Dim())
+		if ring == 0 {
+			emptyShell = len(poly.Data) == 0
+		} else if len(poly.Data) > ringStart {
+			poly.Holes = append(poly.Holes, ringStart/p.layout.Dim())
+		}
+		if !p.accept(',') {
+			break
+		}
+	}
+	if err := p.expect(')'); err != nil {
+		return err
+	}
+
+	poly.Dim = p.layout.Dim()
+	// an empty exterior makes the polygon empty, whatever its holes
+	if !emptyShell {
+		g.Polygons = append(g.Polygons, poly)
+	}
+	return nil
+}
+
+// drop the closing duplicate of the ring starting at data[start:]
+func closeRing(data []float64, start, dim int) []float64 {
+	end := len(data)
+	if end-start < 2*dim {
+		return data
+	}
+	for i := 0; i < dim; i++ {
+		if data[start+i] != data[end-dim+i] {
+			return data
+		}
+	}
+	return data[:end-dim]
+}
+
+// Form selects how a triangulation is written.
+type Form int
+
+const (
+	// TIN writes a single TIN (triangulated irregular network) geometry.
+	TIN Form = iota
+	// GeometryCollection writes a GEOMETRYCOLLECTION of TRIANGLE geometries.
+	GeometryCollection
+)
+
+// MarshalWKT writes triangles over data (layout.Dim() coordinates per vertex) as WKT in the given form.
+func MarshalWKT(form Form, layout Layout, data []float64, triangles []int) string {
+	var b strings.Builder
+	dim := layout.Dim()
+
+	if form == TIN {
+		b.WriteString("TIN" + layout.tag())
+	} else {
+		b.WriteString("GEOMETRYCOLLECTION" + layout.tag())
+	}
+	if len(triangles) == 0 {
+		b.WriteString(" EMPTY")
+		return b.String()
+	}
+
+	b.WriteString(" (")
+	for t := 0; t+2 < len(triangles); t += 3 {
+		if t > 0 {
+			b.WriteString(", ")
+		}
+		if form == GeometryCollection {
+			b.WriteString("TRIANGLE" + layout.tag() + " ")
+		}
+		b.WriteString("((")
+		for k, i := range [4]int{triangles[t], triangles[t+1], triangles[t+2], triangles[t]} {
+			if k > 0 {
+				b.WriteString(", ")
+			}
+			for c := 0; c < dim; c++ {
+				if c > 0 {
+					b.WriteByte(' ')
+				}
+				b.WriteString(strconv.FormatFloat(data[i*dim+c], 'f', -1, 64))
+			}
+		}
+		b.WriteString("))")
+	}
+	b.WriteString(")")
+	return b.String()
+}
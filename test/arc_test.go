@@ -0,0 +1,37 @@
+package earcut
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"earcut-go/pkg/earcut/svgpath"
+)
+
+func TestArcSegments(t *testing.T) {
+	// a unit circle drawn as two half arcs, each split into n chords that stray
+	// 1 - cos(pi/2n) from it: n is the fewest chords within tolerance
+	const circle = "M1 0 A1 1 0 0 1 -1 0 A1 1 0 0 1 1 0Z"
+	for _, tolerance := range []float64{0.5, 0.01, 1e-6, 1e-8} {
+		rings, err := svgpath.Parse(circle, tolerance)
+		require.NoError(t, err)
+		require.Len(t, rings, 1)
+		n := len(rings[0]) / 2 / 2
+		assert.LessOrEqual(t, 1-math.Cos(math.Pi/float64(2*n)), tolerance*(1+1e-6), "tolerance %v", tolerance)
+		assert.Greater(t, 1-math.Cos(math.Pi/float64(2*(n-1))), tolerance*(1-1e-6), "tolerance %v", tolerance)
+	}
+
+	// a tolerance beyond the radius leaves a half arc a single chord
+	rings, err := svgpath.Parse("M1 0 A1 1 0 0 1 -1 0 L-1 -2 L1 -2Z", 10)
+	require.NoError(t, err)
+	assert.Len(t, rings[0], 4*2)
+
+	// tiny tolerances are capped rather than rounding the step to zero
+	for _, tolerance := range []float64{1e-17, 1e-300, math.SmallestNonzeroFloat64} {
+		rings, err := svgpath.Parse(circle, tolerance)
+		require.NoError(t, err)
+		assert.Len(t, rings[0], 2*2<<16, "tolerance %v", tolerance)
+	}
+}
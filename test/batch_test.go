@@ -0,0 +1,71 @@
+package earcut
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"earcut-go/pkg/earcut"
+)
+
+func TestTriangulateBatch(t *testing.T) {
+	var polygons []earcut.Polygon
+	for i := 0; i < 200; i++ {
+		data, holes := footprint()
+		if i%3 == 0 {
+			data, holes = circle(3+i, 0, 0, 10), nil
+		}
+		polygons = append(polygons, earcut.Polygon{Data: data, Holes: holes, Dim: 2})
+	}
+	// one bad polygon doesn't stop the others
+	polygons[7] = earcut.Polygon{Data: []float64{0, 0, 1}, Dim: 2}
+
+	results, stats := earcut.TriangulateBatch(context.Background(), polygons, earcut.BatchOptions{Workers: 4})
+	require.Len(t, results, len(polygons))
+
+	vertices, triangles := 0, 0
+	for i, p := range polygons {
+		vertices += len(p.Data) / 2
+		if i == 7 {
+			assert.ErrorIs(t, results[i].Err, earcut.ErrBadDataLength)
+			continue
+		}
+		require.NoError(t, results[i].Err)
+		assert.Equal(t, earcut.Earcut(p.Data, p.Holes, 2), results[i].Triangles)
+		triangles += len(results[i].Triangles) / 3
+	}
+	assert.Equal(t, earcut.BatchStats{Polygons: 200, Failed: 1, Vertices: vertices, Triangles: triangles,
+		Elapsed: stats.Elapsed, Busy: stats.Busy}, stats)
+	assert.Positive(t, stats.Elapsed)
+}
+
+func TestTriangulateBatchCanceled(t *testing.T) {
+	polygons := []earcut.Polygon{{Data: circle(100, 0, 0, 1), Dim: 2}, {Data: circle(50, 0, 0, 1), Dim: 2}}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results, stats := earcut.TriangulateBatch(ctx, polygons, earcut.BatchOptions{})
+	for _, r := range results {
+		assert.ErrorIs(t, r.Err, context.Canceled)
+	}
+	assert.Equal(t, 2, stats.Failed)
+
+	results, stats = earcut.TriangulateBatch(ctx, nil, earcut.BatchOptions{})
+	assert.Empty(t, results)
+	assert.Zero(t, stats.Polygons)
+}
+
+func BenchmarkTriangulateBatch(b *testing.B) {
+	var polygons []earcut.Polygon
+	for i := 0; i < 1000; i++ {
+		data, holes := footprint()
+		polygons = append(polygons, earcut.Polygon{Data: data, Holes: holes, Dim: 2})
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		earcut.TriangulateBatch(context.Background(), polygons, earcut.BatchOptions{})
+	}
+}
@@ -0,0 +1,154 @@
+package earcut
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// buildCLI compiles cmd/earcut into a temporary directory
+func buildCLI(t *testing.T) string {
+	t.Helper()
+	bin := filepath.Join(t.TempDir(), "earcut")
+	out, err := exec.Command("go", "build", "-o", bin, "../cmd/earcut").CombinedOutput()
+	require.NoError(t, err, string(out))
+	return bin
+}
+
+// runCLI runs the binary with stdin and returns stdout and the exit code
+func runCLI(t *testing.T, bin, stdin string, args ...string) (string, int) {
+	t.Helper()
+	cmd := exec.Command(bin, args...)
+	cmd.Stdin = strings.NewReader(stdin)
+	out, err := cmd.Output()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return string(out), exitErr.ExitCode()
+	}
+	require.NoError(t, err)
+	return string(out), 0
+}
+
+func TestCLI(t *testing.T) {
+	bin := buildCLI(t)
+
+	var res struct {
+		Vertices   []float64 `json:"vertices"`
+		Dimensions int       `json:"dimensions"`
+		Triangles  []int     `json:"triangles"`
+		Deviation  *float64  `json:"deviation"`
+	}
+
+	inputs := map[string]string{
+		"rings":   `[[[0,0],[10,0],[10,10],[0,10]],[[2,2],[2,8],[8,8],[8,2]]]`,
+		"flat":    `{"vertices":[0,0,10,0,10,10,0,10,2,2,2,8,8,8,8,2],"holes":[4],"dimensions":2}`,
+		"geojson": `{"type":"Polygon","coordinates":[[[0,0],[10,0],[10,10],[0,10],[0,0]],[[2,2],[2,8],[8,8],[8,2],[2,2]]]}`,
+		"wkt":     `POLYGON ((0 0, 10 0, 10 10, 0 10, 0 0), (2 2, 2 8, 8 8, 8 2, 2 2))`,
+	}
+	for name, input := range inputs {
+		out, code := runCLI(t, bin, input)
+		assert.Equal(t, 0, code, name)
+		require.NoError(t, json.Unmarshal([]byte(out), &res), name)
+		assert.Equal(t, 2, res.Dimensions, name)
+		assert.Equal(t, 16, len(res.Vertices), name)
+		assert.Equal(t, 8*3, len(res.Triangles), name)
+		require.NotNil(t, res.Deviation, name)
+		assert.Zero(t, *res.Deviation, name)
+	}
+}
+
+func TestCLIOutputFiles(t *testing.T) {
+	bin := buildCLI(t)
+	dir := t.TempDir()
+	svg := filepath.Join(dir, "out.svg")
+	obj := filepath.Join(dir, "out.obj")
+	stl := filepath.Join(dir, "out.stl")
+	ply := filepath.Join(dir, "out.ply")
+	glb := filepath.Join(dir, "out.glb")
+	input := filepath.Join(dir, "in.wkt")
+	require.NoError(t, os.WriteFile(input, []byte("POLYGON ((0 0, 1 0, 1 1, 0 1, 0 0))"), 0o644))
+
+	_, code := runCLI(t, bin, "", "-svg", svg, "-obj", obj, "-stl", stl, "-ply", ply, "-glb", glb, input)
+	assert.Equal(t, 0, code)
+
+	b, err := os.ReadFile(svg)
+	require.NoError(t, err)
+	assert.Equal(t, 2+1, strings.Count(string(b), "<polygon"))
+
+	b, err = os.ReadFile(obj)
+	require.NoError(t, err)
+	assert.Equal(t, 4, strings.Count(string(b), "v "))
+	assert.Equal(t, 2, strings.Count(string(b), "f "))
+
+	b, err = os.ReadFile(stl)
+	require.NoError(t, err)
+	assert.Len(t, b, 84+2*50)
+
+	b, err = os.ReadFile(ply)
+	require.NoError(t, err)
+	assert.Contains(t, string(b), "element face 2\n")
+
+	b, err = os.ReadFile(glb)
+	require.NoError(t, err)
+	assert.Equal(t, "glTF", string(b[:4]))
+}
+
+func TestCLIGLBNodes(t *testing.T) {
+	bin := buildCLI(t)
+	nodeNames := func(input string) []string {
+		glb := filepath.Join(t.TempDir(), "out.glb")
+		_, code := runCLI(t, bin, input, "-glb", glb)
+		require.Equal(t, 0, code)
+		b, err := os.ReadFile(glb)
+		require.NoError(t, err)
+		doc, _ := readGLB(t, b)
+		var names []string
+		for _, node := range doc["nodes"].([]any) {
+			names = append(names, node.(map[string]any)["name"].(string))
+		}
+		return names
+	}
+
+	// one node per polygon, named after its feature
+	assert.Equal(t, []string{"park", "park", "7", "polygon 3"}, nodeNames(`{"type":"FeatureCollection","features":[
+		{"type":"Feature","properties":{"name":"park"},"geometry":{"type":"MultiPolygon","coordinates":[
+			[[[0,0],[1,0],[1,1],[0,0]]],
+			[[[5,5],[6,5],[6,6],[5,5]]]
+		]}},
+		{"type":"Feature","id":7,"properties":null,"geometry":{"type":"Polygon","coordinates":[[[0,0],[1,0],[1,1],[0,1],[0,0]]]}},
+		{"type":"Feature","properties":{},"geometry":{"type":"Polygon","coordinates":[[[0,0],[1,0],[1,1],[0,1],[0,0]]]}}
+	]}`))
+	assert.Equal(t, []string{"polygon 0", "polygon 1"}, nodeNames("MULTIPOLYGON (((0 0, 1 0, 1 1, 0 0)), ((5 5, 6 5, 6 6, 5 5)))"))
+
+	// a degenerate polygon has no triangles but still its feature's name
+	assert.Equal(t, []string{"line", "square"}, nodeNames(`{"type":"FeatureCollection","features":[
+		{"type":"Feature","properties":{"name":"line"},"geometry":{"type":"Polygon","coordinates":[[[0,0],[1,0],[2,0],[0,0]]]}},
+		{"type":"Feature","id":"square","properties":null,"geometry":{"type":"Polygon","coordinates":[[[0,0],[1,0],[1,1],[0,1],[0,0]]]}}
+	]}`))
+}
+
+func TestCLIExitCodes(t *testing.T) {
+	bin := buildCLI(t)
+
+	_, code := runCLI(t, bin, "POINT (1 1)")
+	assert.Equal(t, 1, code)
+
+	_, code = runCLI(t, bin, `{"vertices":[0,0,1,0,1,1],"dimensions":1}`)
+	assert.Equal(t, 1, code)
+
+	_, code = runCLI(t, bin, "[[[0,0],[1,0],[1,1]]]", "-format", "csv")
+	assert.Equal(t, 2, code)
+
+	_, code = runCLI(t, bin, "", "-nope")
+	assert.Equal(t, 2, code)
+
+	_, code = runCLI(t, bin, "", "does-not-exist.json")
+	assert.Equal(t, 1, code)
+}
@@ -0,0 +1,106 @@
+package earcut
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"earcut-go/pkg/earcut"
+)
+
+// cancelAfter is a context that reports cancellation once Err has been called n times
+type cancelAfter struct {
+	context.Context
+	n int
+}
+
+func (c *cancelAfter) Err() error {
+	c.n--
+	if c.n < 0 {
+		return context.Canceled
+	}
+	return nil
+}
+
+func TestEarcutContextBudget(t *testing.T) {
+	data := append(circle(2000, 0, 0, 100), circle(50, 10, 10, 20)...)
+	holes := []int{2000}
+	full := earcut.Earcut(data, holes, 2)
+
+	indices, err := earcut.EarcutContext(context.Background(), data, holes, 2, earcut.Options{MaxWork: 500})
+	assert.ErrorIs(t, err, earcut.ErrBudgetExceeded)
+	assert.Less(t, len(indices), len(full))
+	checkIndices(t, indices, len(data)/2)
+
+	indices, err = earcut.EarcutContext(context.Background(), data, holes, 2, earcut.Options{MaxWork: 1 << 30})
+	require.NoError(t, err)
+	assert.Equal(t, full, indices)
+}
+
+func TestEarcutContextCancel(t *testing.T) {
+	data := circle(5000, 0, 0, 100)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	indices, err := earcut.EarcutContext(ctx, data, nil, 2, earcut.Options{})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, indices)
+
+	// cancelled in the middle of the ear slicing loop
+	indices, err = earcut.EarcutContext(&cancelAfter{context.Background(), 2}, data, nil, 2, earcut.Options{})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.NotEmpty(t, indices)
+	assert.Less(t, len(indices), 4998*3)
+	checkIndices(t, indices, len(data)/2)
+}
+
+func TestEarcutContextCancelLongSteps(t *testing.T) {
+	// without hashing every ear test walks the whole ring, so the context has to be checked
+	// after a few steps rather than a fixed number of them
+	data := circle(20000, 0, 0, 100)
+	indices, err := earcut.EarcutContext(&cancelAfter{context.Background(), 2}, data, nil, 2, earcut.Options{Hashing: earcut.HashNever})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.NotEmpty(t, indices)
+	assert.Less(t, len(indices), 10*3)
+	checkIndices(t, indices, len(data)/2)
+}
+
+func TestEarcutContextInvalidInput(t *testing.T) {
+	_, err := earcut.EarcutContext(context.Background(), []float64{0, 0, 1}, nil, 2, earcut.Options{})
+	assert.ErrorIs(t, err, earcut.ErrBadDataLength)
+}
+
+func TestTriangulateContextReuse(t *testing.T) {
+	var tr earcut.Triangulator
+	tr.Options.MaxWork = 10
+	square := []float64{0, 0, 1, 0, 1, 1, 0, 1}
+
+	_, err := tr.TriangulateContext(context.Background(), nil, circle(100, 0, 0, 1), nil, 2)
+	assert.ErrorIs(t, err, earcut.ErrBudgetExceeded)
+
+	// the budget starts over with every call
+	dst := []int{7}
+	dst, err = tr.TriangulateContext(context.Background(), dst, square, nil, 2)
+	require.NoError(t, err)
+	assert.Equal(t, append([]int{7}, earcut.Earcut(square, nil, 2)...), dst)
+}
+
+func TestMaxWorkNeedsError(t *testing.T) {
+	data := circle(200, 0, 0, 100)
+	full := earcut.Earcut(data, nil, 2)
+	opts := earcut.Options{MaxWork: 10}
+
+	// the functions without an error to report the cut-off with ignore the budget
+	assert.Equal(t, full, earcut.EarcutWithOptions(data, nil, 2, opts))
+	tr := earcut.Triangulator{Options: opts}
+	assert.Equal(t, full, tr.Triangulate(nil, data, nil, 2))
+
+	indices, err := earcut.TriangulateT(&tr, []uint32{}, data, nil, 2)
+	assert.ErrorIs(t, err, earcut.ErrBudgetExceeded)
+	assert.Less(t, len(indices), len(full))
+
+	_, err = earcut.EarcutT[float64, uint32](data, nil, 2)
+	require.NoError(t, err)
+}
@@ -0,0 +1,161 @@
+package earcut
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"earcut-go/pkg/earcut"
+)
+
+// whether every edge of every ring is still an edge of some triangle
+func hasRingEdges(data []float64, holes []int, triangles []int) bool {
+	edges := map[[2]int]bool{}
+	for t := 0; t < len(triangles); t += 3 {
+		for k := 0; k < 3; k++ {
+			a, b := triangles[t+k], triangles[t+(k+1)%3]
+			edges[[2]int{min(a, b), max(a, b)}] = true
+		}
+	}
+	n := len(data) / 2
+	starts := append([]int{0}, holes...)
+	for r, start := range starts {
+		end := n
+		if r+1 < len(starts) {
+			end = starts[r+1]
+		}
+		for i := start; i < end; i++ {
+			j := i + 1
+			if j == end {
+				j = start
+			}
+			if !edges[[2]int{min(i, j), max(i, j)}] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// whether any triangle has the far vertex of a neighbour clearly inside its circumcircle,
+// not counting neighbours across ring edges
+func hasIllegalEdge(data []float64, holes []int, triangles []int) bool {
+	ring := map[[2]int]bool{}
+	starts := append([]int{0}, holes...)
+	for r, start := range starts {
+		end := len(data) / 2
+		if r+1 < len(starts) {
+			end = starts[r+1]
+		}
+		for i := start; i < end; i++ {
+			j := i + 1
+			if j == end {
+				j = start
+			}
+			ring[[2]int{min(i, j), max(i, j)}] = true
+		}
+	}
+
+	opposite := map[[2]int]int{}
+	for t := 0; t < len(triangles); t += 3 {
+		for k := 0; k < 3; k++ {
+			opposite[[2]int{triangles[t+k], triangles[t+(k+1)%3]}] = triangles[t+(k+2)%3]
+		}
+	}
+	for t := 0; t < len(triangles); t += 3 {
+		for k := 0; k < 3; k++ {
+			a, b, c := triangles[t+k], triangles[t+(k+1)%3], triangles[t+(k+2)%3]
+			d, ok := opposite[[2]int{b, a}]
+			if !ok || ring[[2]int{min(a, b), max(a, b)}] {
+				continue
+			}
+			// circumcircle test relative to d, scaled to be dimensionless
+			ax, ay := data[2*a]-data[2*d], data[2*a+1]-data[2*d+1]
+			bx, by := data[2*b]-data[2*d], data[2*b+1]-data[2*d+1]
+			cx, cy := data[2*c]-data[2*d], data[2*c+1]-data[2*d+1]
+			det := (ax*ax+ay*ay)*(bx*cy-by*cx) + (bx*bx+by*by)*(cx*ay-cy*ax) + (cx*cx+cy*cy)*(ax*by-ay*bx)
+			scale := math.Pow(math.Abs(ax)+math.Abs(ay)+math.Abs(bx)+math.Abs(by)+math.Abs(cx)+math.Abs(cy), 4)
+			if det > 1e-9*scale && earcut.Orient2D(data[2*c], data[2*c+1], data[2*a], data[2*a+1], data[2*d], data[2*d+1]) > 0 &&
+				earcut.Orient2D(data[2*c], data[2*c+1], data[2*d], data[2*d+1], data[2*b], data[2*b+1]) > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// a 1 unit high strip with a vertex every unit along its long sides
+func strip(n int) []float64 {
+	var data []float64
+	for i := 0; i <= n; i++ {
+		data = append(data, float64(i), 0)
+	}
+	for i := n; i >= 0; i-- {
+		data = append(data, float64(i), 1)
+	}
+	return data
+}
+
+func TestRefineDelaunay(t *testing.T) {
+	shapes := []struct {
+		name  string
+		data  []float64
+		holes []int
+	}{
+		{"strip", strip(20), nil},
+		{"ring", append(circle(60, 0, 0, 10), circle(30, 1, -2, 4)...), []int{60}},
+		{"building", []float64{
+			661, 112, 661, 96, 666, 96, 666, 87, 743, 87, 771, 87, 771, 114, 750, 114,
+			750, 113, 742, 113, 742, 106, 710, 106, 710, 113, 666, 113, 666, 112,
+		}, nil},
+		{"holes", []float64{0, 0, 100, 0, 100, 100, 0, 100, 20, 20, 40, 20, 40, 40, 20, 40, 60, 60, 80, 60, 70, 80}, []int{4, 8}},
+	}
+
+	for _, s := range shapes {
+		t.Run(s.name, func(t *testing.T) {
+			triangles := earcut.Earcut(s.data, s.holes, 2)
+			before := earcut.MinAngle(s.data, 2, triangles)
+			count := len(triangles)
+
+			flips := earcut.RefineDelaunay(s.data, s.holes, 2, triangles)
+			assert.Greater(t, flips, 0)
+			assert.Equal(t, count, len(triangles))
+			assert.Greater(t, earcut.MinAngle(s.data, 2, triangles), before)
+
+			assert.Less(t, earcut.Deviation(s.data, s.holes, 2, triangles), 1e-12)
+			assert.True(t, hasRingEdges(s.data, s.holes, triangles))
+			assert.False(t, hasIllegalEdge(s.data, s.holes, triangles))
+
+			// already Delaunay
+			assert.Equal(t, 0, earcut.RefineDelaunay(s.data, s.holes, 2, triangles))
+		})
+	}
+}
+
+func TestRefineDelaunayKeepsConstraints(t *testing.T) {
+	// a thin notch: the Delaunay triangulation of the points alone would cross the notch's edges
+	data := []float64{0, 0, 10, 0, 10, 10, 5.2, 10, 5, 1, 4.8, 10, 0, 10}
+	triangles := earcut.Earcut(data, nil, 2)
+	earcut.RefineDelaunay(data, nil, 2, triangles)
+	assert.True(t, hasRingEdges(data, nil, triangles))
+	assert.Less(t, earcut.Deviation(data, nil, 2, triangles), 1e-12)
+}
+
+func TestMinAngle(t *testing.T) {
+	assert.InDelta(t, 60, earcut.MinAngle([]float64{0, 0, 2, 0, 1, math.Sqrt(3)}, 2, []int{0, 1, 2}), 1e-9)
+	assert.InDelta(t, 45, earcut.MinAngle([]float64{0, 0, 0, 1, 0, 0, 1, 1, 0}, 3, []int{0, 1, 2}), 1e-9)
+	assert.Equal(t, 0.0, earcut.MinAngle([]float64{0, 0, 1, 0, 2, 0}, 2, []int{0, 1, 2}))
+	assert.Equal(t, 0.0, earcut.MinAngle(nil, 2, nil))
+}
+
+func TestRefineDelaunayFixtures(t *testing.T) {
+	// degenerate and self-touching input must keep the area it covered
+	for _, name := range []string{"building", "steiner", "degenerate", "bad-hole", "touching2", "hourglass", "issue45"} {
+		data, holes, dim := loadFixture(t, name)
+		triangles := earcut.Earcut(data, holes, dim)
+		before := earcut.Deviation(data, holes, dim, triangles)
+		earcut.RefineDelaunay(data, holes, dim, triangles)
+		assert.InDelta(t, before, earcut.Deviation(data, holes, dim, triangles), 1e-9, name)
+	}
+}
@@ -4,11 +4,12 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"earcut-go/pkg/earcut"
 )
 
-// todo: port more tests from JS version
+// fixture-based tests ported from the JS version live in fixtures_test.go
 
 func TestIndices2D(t *testing.T) {
 	data := []float64{10, 0, 0, 50, 60, 60, 70, 10}
@@ -41,14 +42,18 @@ func TestTrianglesBuilding(t *testing.T) {
 	assert.Equal(t, 39, len(indices), "Should generate 13 triangles (39 indices)")
 }
 
-// func TestComplexPolygon(t *testing.T) {
-// 	data := []float64{
-// 		7, 18, 7, 15, 5, 15,
-// 		7, 13, 7, 15, 17, 17,
-// 	}
-// 	indices := Triangulate(data, nil, 2)
-// 	assert.Equal(t, 6, len(indices), "Should generate 6 triangles (18 indices)")
-// }
+func TestEmpty(t *testing.T) {
+	assert.Equal(t, []int{}, earcut.Triangulate([]float64{}, []int{}, 2))
+}
+
+func TestComplexPolygon(t *testing.T) {
+	data := []float64{
+		7, 18, 7, 15, 5, 15,
+		7, 13, 7, 15, 17, 17,
+	}
+	indices := earcut.Triangulate(data, nil, 2)
+	assert.Equal(t, 6, len(indices), "Should generate 2 triangles (6 indices)")
+}
 
 func TestComplexPolygonWithHole(t *testing.T) {
 	data := []float64{
@@ -62,24 +67,23 @@ func TestComplexPolygonWithHole(t *testing.T) {
 	assert.Equal(t, 8*3, len(indices), "Should generate 8 triangles (24 indices)")
 }
 
-// func TestComplexPolygonWithHoles(t *testing.T) {
-// 	data := []float64{
-// 		810, 2828, 818, 2828, 832, 2818, 844, 2806, 855, 2808,
-// 		866, 2816, 867, 2824, 876, 2827, 883, 2834, 875, 2834,
-// 		867, 2840, 878, 2838, 889, 2844, 880, 2847, 870, 2847,
-// 		860, 2864, 852, 2879, 847, 2867, 810, 2828, 810, 2828,
-// 		818, 2834, 823, 2833, 831, 2828, 839, 2829, 839, 2837,
-// 		851, 2845, 847, 2835, 846, 2827, 847, 2827, 837, 2827,
-// 		840, 2815, 835, 2823, 818, 2834, 818, 2834, 857, 2846,
-// 		864, 2850, 866, 2839, 857, 2846, 857, 2846, 848, 2863,
-// 		848, 2866, 854, 2852, 846, 2854, 847, 2862, 838, 2851,
-// 		838, 2859, 848, 2863, 848, 2863,
-// 	}
-// 	holes := []int{20, 34, 39}
-// 	indices := Triangulate(data, holes, 2)
-// 	fmt.Println(indices)
-// 	assert.Equal(t, 42*3, len(indices), "Should generate 14 triangles (42 indices)")
-// }
+func TestComplexPolygonWithHoles(t *testing.T) {
+	data := []float64{
+		810, 2828, 818, 2828, 832, 2818, 844, 2806, 855, 2808,
+		866, 2816, 867, 2824, 876, 2827, 883, 2834, 875, 2834,
+		867, 2840, 878, 2838, 889, 2844, 880, 2847, 870, 2847,
+		860, 2864, 852, 2879, 847, 2867, 810, 2828, 810, 2828,
+		818, 2834, 823, 2833, 831, 2828, 839, 2829, 839, 2837,
+		851, 2845, 847, 2835, 846, 2827, 847, 2827, 837, 2827,
+		840, 2815, 835, 2823, 818, 2834, 818, 2834, 857, 2846,
+		864, 2850, 866, 2839, 857, 2846, 857, 2846, 848, 2863,
+		848, 2866, 854, 2852, 846, 2854, 847, 2862, 838, 2851,
+		838, 2859, 848, 2863, 848, 2863,
+	}
+	holes := []int{20, 34, 39}
+	indices := earcut.Triangulate(data, holes, 2)
+	assert.Equal(t, 42*3, len(indices), "Should generate 42 triangles (126 indices)")
+}
 
 func TestPolygonWithHoles(t *testing.T) {
 	data := []float64{
@@ -102,3 +106,18 @@ func TestPolygonWithMultipleHoles(t *testing.T) {
 	indices := earcut.Triangulate(data, holes, 2)
 	assert.Equal(t, 30, len(indices), "Should generate 10 triangles (30 indices)")
 }
+
+func TestHoleBridges(t *testing.T) {
+	// two square holes side by side in a square; the right one is joined through the left one
+	data := []float64{0, 0, 10, 0, 10, 10, 0, 10, 2, 2, 2, 4, 4, 4, 4, 2, 6, 2, 6, 4, 8, 4, 8, 2}
+	bridges := earcut.HoleBridges(data, []int{4, 8}, 2)
+	require.Len(t, bridges, 2)
+	assert.Equal(t, [2]int{0, 4}, bridges[0])
+	assert.Contains(t, []int{6, 7}, bridges[1][0])
+	assert.Equal(t, 8, bridges[1][1])
+
+	assert.Empty(t, earcut.HoleBridges(data[:8], nil, 2))
+	// input EarcutE rejects, such as an empty hole
+	assert.Nil(t, earcut.HoleBridges(data, []int{4, 12}, 2))
+	assert.Nil(t, earcut.HoleBridges(data, []int{8, 4}, 2))
+}
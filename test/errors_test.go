@@ -0,0 +1,55 @@
+package earcut
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"earcut-go/pkg/earcut"
+)
+
+func TestEarcutEValid(t *testing.T) {
+	data := []float64{10, 0, 0, 50, 60, 60, 70, 10}
+	indices, err := earcut.EarcutE(data, nil, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 0, 3, 3, 2, 1}, indices)
+}
+
+func TestEarcutEDefaultDimension(t *testing.T) {
+	data := []float64{10, 0, 0, 50, 60, 60, 70, 10}
+	indices, err := earcut.EarcutE(data, nil, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 0, 3, 3, 2, 1}, indices)
+}
+
+func TestEarcutEErrors(t *testing.T) {
+	square := []float64{0, 0, 10, 0, 10, 10, 0, 10, 2, 2, 8, 2, 8, 8}
+
+	tests := []struct {
+		name  string
+		data  []float64
+		holes []int
+		dim   int
+		err   error
+	}{
+		{"dimension one", square, nil, 1, earcut.ErrBadDimension},
+		{"negative dimension", square, nil, -2, earcut.ErrBadDimension},
+		{"odd length", square[:5], nil, 2, earcut.ErrBadDataLength},
+		{"negative hole", square, []int{-1}, 2, earcut.ErrHoleIndexOutOfRange},
+		{"hole at start", square, []int{0}, 2, earcut.ErrHoleIndexOutOfRange},
+		{"hole past end", square, []int{7}, 2, earcut.ErrHoleIndexOutOfRange},
+		{"unsorted holes", square, []int{5, 4}, 2, earcut.ErrUnsortedHoleIndices},
+		{"empty hole", square, []int{4, 4}, 2, earcut.ErrUnsortedHoleIndices},
+		{"nan", []float64{0, 0, math.NaN(), 0, 1, 1}, nil, 2, earcut.ErrNonFiniteCoordinate},
+		{"inf", []float64{0, 0, 1, 0, 1, math.Inf(1)}, nil, 2, earcut.ErrNonFiniteCoordinate},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			indices, err := earcut.EarcutE(tt.data, tt.holes, tt.dim)
+			assert.ErrorIs(t, err, tt.err)
+			assert.Nil(t, indices)
+		})
+	}
+}
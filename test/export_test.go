@@ -0,0 +1,177 @@
+package earcut
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"earcut-go/pkg/earcut"
+	"earcut-go/pkg/earcut/export"
+)
+
+func TestWriteOBJ(t *testing.T) {
+	mesh := export.Mesh{Vertices: []float64{0, 0, 1, 0, 1, 1, 0, 1}, Dim: 2, Triangles: []int{2, 3, 0, 0, 1, 2}}
+	var b bytes.Buffer
+	require.NoError(t, export.WriteOBJ(&b, mesh))
+	assert.Equal(t, "v 0 0 0\nv 1 0 0\nv 1 1 0\nv 0 1 0\nf 3 4 1\nf 1 2 3\n", b.String())
+
+	b.Reset()
+	mesh.Normals = true
+	require.NoError(t, export.WriteOBJ(&b, mesh))
+	assert.Contains(t, b.String(), "vn 0 0 1\nf 3//1 4//1 1//1\nvn 0 0 1\nf 1//2 2//2 3//2\n")
+}
+
+func TestWriteSTL(t *testing.T) {
+	// a vertical triangle in the xz plane, with an extra coordinate that isn't written
+	mesh := export.Mesh{Vertices: []float64{0, 0, 0, 7, 1, 0, 0, 7, 0, 0, 1, 7}, Dim: 4, Triangles: []int{0, 1, 2}}
+	var b bytes.Buffer
+	require.NoError(t, export.WriteSTL(&b, mesh, "wall"))
+	assert.Equal(t, "solid wall\nfacet normal 0 -1 0\nouter loop\nvertex 0 0 0\nvertex 1 0 0\nvertex 0 0 1\n"+
+		"endloop\nendfacet\nendsolid wall\n", b.String())
+
+	b.Reset()
+	require.NoError(t, export.WriteBinarySTL(&b, mesh))
+	data := b.Bytes()
+	require.Len(t, data, 80+4+50)
+	assert.Equal(t, uint32(1), binary.LittleEndian.Uint32(data[80:]))
+	var floats []float32
+	for i := 84; i < 84+48; i += 4 {
+		floats = append(floats, math.Float32frombits(binary.LittleEndian.Uint32(data[i:])))
+	}
+	assert.Equal(t, []float32{0, -1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1}, floats)
+}
+
+func TestWritePLY(t *testing.T) {
+	mesh := export.Mesh{Vertices: []float64{0, 0, 1, 0, 0, 1}, Dim: 2, Triangles: []int{0, 1, 2}, Normals: true}
+	var b bytes.Buffer
+	require.NoError(t, export.WritePLY(&b, mesh))
+	header, body, ok := strings.Cut(b.String(), "end_header\n")
+	require.True(t, ok)
+	assert.Contains(t, header, "element vertex 3\n")
+	assert.Contains(t, header, "element face 1\nproperty list uchar int vertex_indices\nproperty float nx\n")
+	assert.Equal(t, "0 0 0\n1 0 0\n0 1 0\n3 0 1 2 0 0 1\n", body)
+}
+
+func TestExportInvalidMesh(t *testing.T) {
+	for _, mesh := range []export.Mesh{
+		{Vertices: []float64{0, 0, 1}, Dim: 1},
+		{Vertices: []float64{0, 0, 1}, Dim: 2},
+		{Vertices: []float64{0, 0, 1, 0, 0, 1}, Dim: 2, Triangles: []int{0, 1}},
+		{Vertices: []float64{0, 0, 1, 0, 0, 1}, Dim: 2, Triangles: []int{0, 1, 3}},
+	} {
+		assert.ErrorIs(t, export.WriteOBJ(&bytes.Buffer{}, mesh), export.ErrInvalidMesh)
+		assert.ErrorIs(t, export.WriteBinarySTL(&bytes.Buffer{}, mesh), export.ErrInvalidMesh)
+	}
+}
+
+// the JSON and binary chunks of a GLB file
+func readGLB(t *testing.T, data []byte) (doc map[string]any, bin []byte) {
+	t.Helper()
+	require.GreaterOrEqual(t, len(data), 20)
+	assert.Equal(t, "glTF", string(data[:4]))
+	assert.Equal(t, uint32(2), binary.LittleEndian.Uint32(data[4:]))
+	require.Equal(t, uint32(len(data)), binary.LittleEndian.Uint32(data[8:]))
+	for i := 12; i < len(data); {
+		length, kind := int(binary.LittleEndian.Uint32(data[i:])), string(data[i+4:i+8])
+		require.Zero(t, length%4, "chunk %q isn't padded", kind)
+		chunk := data[i+8 : i+8+length]
+		switch kind {
+		case "JSON":
+			require.NoError(t, json.Unmarshal(chunk, &doc))
+		case "BIN\x00":
+			bin = chunk
+		}
+		i += 8 + length
+	}
+	return doc, bin
+}
+
+func TestWriteGLB(t *testing.T) {
+	square := export.Mesh{Vertices: []float64{10, 20, 12, 20, 12, 22, 10, 22}, Dim: 2, Triangles: []int{2, 3, 0, 0, 1, 2}}
+	var ring []float64
+	for i := 0; i < 300; i++ {
+		a := 2 * math.Pi * float64(i) / 300
+		ring = append(ring, math.Cos(a), math.Sin(a), 5)
+	}
+	disc := export.Mesh{Vertices: ring, Dim: 3, Triangles: earcut.Earcut(ring, nil, 3)}
+	empty := export.Mesh{Dim: 2}
+
+	var b bytes.Buffer
+	require.NoError(t, export.WriteGLB(&b, []export.Mesh{square, disc, empty}, []string{"lot", "pond"}))
+	doc, bin := readGLB(t, b.Bytes())
+
+	nodes := doc["nodes"].([]any)
+	require.Len(t, nodes, 3)
+	assert.Equal(t, "lot", nodes[0].(map[string]any)["name"])
+	assert.Equal(t, []any{11.0, 0.0, -21.0}, nodes[0].(map[string]any)["translation"])
+	assert.Equal(t, "pond", nodes[1].(map[string]any)["name"])
+	assert.NotContains(t, nodes[2], "mesh")
+	assert.Len(t, doc["meshes"], 2)
+
+	accessors := doc["accessors"].([]any)
+	views := doc["bufferViews"].([]any)
+	require.Len(t, accessors, 4)
+	for _, v := range views {
+		assert.Zero(t, int(v.(map[string]any)["byteOffset"].(float64))%4)
+	}
+	position := accessors[0].(map[string]any)
+	assert.Equal(t, []any{-1.0, 0.0, -1.0}, position["min"])
+	assert.Equal(t, []any{1.0, 0.0, 1.0}, position["max"])
+	// up to 255 vertices fit bytes, 300 need shorts
+	assert.Equal(t, 5121.0, accessors[1].(map[string]any)["componentType"])
+	assert.Equal(t, 5123.0, accessors[3].(map[string]any)["componentType"])
+	assert.Equal(t, float64(len(disc.Triangles)), accessors[3].(map[string]any)["count"])
+	assert.Equal(t, float64(len(bin)), doc["buffers"].([]any)[0].(map[string]any)["byteLength"])
+
+	// the square's indices and positions read back from the buffer
+	view := views[1].(map[string]any)
+	offset := int(view["byteOffset"].(float64))
+	assert.Equal(t, []byte{2, 3, 0, 0, 1, 2}, bin[offset:offset+6])
+	var corner []float32
+	for i := 0; i < 12; i += 4 {
+		corner = append(corner, math.Float32frombits(binary.LittleEndian.Uint32(bin[i:])))
+	}
+	assert.Equal(t, []float32{-1, 0, 1}, corner)
+
+	assert.ErrorIs(t, export.WriteGLB(&bytes.Buffer{}, []export.Mesh{{Vertices: []float64{0, 0}, Dim: 2, Triangles: []int{0, 1, 2}}}, nil), export.ErrInvalidMesh)
+}
+
+func TestWriteSVG(t *testing.T) {
+	polygon := earcut.Polygon{Data: []float64{0, 0, 10, 0, 10, 10, 0, 10, 4, 4, 4, 6, 6, 6, 6, 4}, Holes: []int{4}, Dim: 2}
+	triangles := earcut.Earcut(polygon.Data, polygon.Holes, polygon.Dim)
+
+	var b bytes.Buffer
+	require.NoError(t, export.WriteSVG(&b, []earcut.Polygon{polygon}, triangles, export.SVGOptions{Size: 100, Labels: true, Bridges: true}))
+	svg := b.String()
+	assert.Contains(t, svg, `width="100" height="100"`)
+	assert.Equal(t, len(triangles)/3+2, strings.Count(svg, "<polygon"))
+	assert.Equal(t, 1, strings.Count(svg, "<line"))
+	assert.Equal(t, 8, strings.Count(svg, "<text"))
+	// vertex 6 at (6, 6) is 60% across and 40% down the 60 pixels inside the margin
+	assert.Contains(t, svg, `<text x="39" y="21">6</text>`)
+	// a correct triangulation leaves the red polygon underneath hidden
+	assert.Equal(t, 1, strings.Count(svg, "#e31a1c"))
+
+	// the top left half of the square is left uncovered, and the triangle poking out of it and
+	// the two overlapping ones are drawn red
+	b.Reset()
+	square := earcut.Polygon{Data: []float64{0, 0, 10, 0, 10, 10, 0, 10, 20, 0}, Dim: 2}
+	require.NoError(t, export.WriteSVG(&b, []earcut.Polygon{square}, []int{0, 1, 2, 1, 4, 2, 0, 1, 3}, export.SVGOptions{}))
+	assert.Equal(t, 1+3, strings.Count(b.String(), "#e31a1c"))
+	assert.NotContains(t, b.String(), "<text")
+
+	assert.ErrorIs(t, export.WriteSVG(&bytes.Buffer{}, []earcut.Polygon{square}, []int{0, 1, 5}, export.SVGOptions{}), export.ErrInvalidMesh)
+	// an empty hole, at the end or in the middle
+	for _, holes := range [][]int{{5}, {3, 3}} {
+		polygon := earcut.Polygon{Data: square.Data, Holes: holes, Dim: 2}
+		err := export.WriteSVG(&bytes.Buffer{}, []earcut.Polygon{polygon}, nil, export.SVGOptions{Bridges: true})
+		assert.ErrorIs(t, err, export.ErrInvalidMesh, "holes %v", holes)
+	}
+	require.NoError(t, export.WriteSVG(&bytes.Buffer{}, []earcut.Polygon{{}}, nil, export.SVGOptions{Bridges: true}))
+}
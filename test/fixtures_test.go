@@ -0,0 +1,120 @@
+package earcut
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"earcut-go/pkg/earcut"
+	"earcut-go/pkg/earcut/export"
+)
+
+// expectations keeps the layout of mapbox/earcut's test/expected.json, so fixtures ported from
+// it can be dropped into test/fixtures together with their entries
+type expectations struct {
+	// Triangles is the expected triangle count of each fixture
+	Triangles map[string]int `json:"triangles"`
+	// Errors is the maximum deviation of each fixture, 0 when absent
+	Errors map[string]float64 `json:"errors"`
+}
+
+func readExpectations(t *testing.T, path string) expectations {
+	t.Helper()
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var expected expectations
+	require.NoError(t, json.Unmarshal(b, &expected))
+	return expected
+}
+
+func loadFixture(t *testing.T, name string) (data []float64, holes []int, dim int) {
+	t.Helper()
+	return loadFixtureFrom(t, "fixtures", name)
+}
+
+func loadFixtureFrom(t *testing.T, dir, name string) (data []float64, holes []int, dim int) {
+	t.Helper()
+	b, err := os.ReadFile(filepath.Join(dir, name+".json"))
+	require.NoError(t, err)
+	var rings [][][]float64
+	require.NoError(t, json.Unmarshal(b, &rings))
+	return earcut.Flatten(rings)
+}
+
+// runFixtures checks the triangle count and deviation of every fixture in expected, read from dir
+func runFixtures(t *testing.T, dir string, expected expectations) {
+	names := make([]string, 0, len(expected.Triangles))
+	for name := range expected.Triangles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		t.Run(name, func(t *testing.T) {
+			data, holes, dim := loadFixtureFrom(t, dir, name)
+			indices := earcut.Earcut(data, holes, dim)
+			dumpSVGOnFailure(t, data, holes, dim, indices)
+
+			assert.Equal(t, expected.Triangles[name], len(indices)/3, "triangle count")
+			assert.LessOrEqual(t, earcut.Deviation(data, holes, dim, indices), expected.Errors[name], "deviation")
+		})
+	}
+}
+
+// dumpSVGOnFailure writes a drawing of the polygon and its triangles when t fails, into
+// $EARCUT_SVG_DIR or the system's temporary directory, and logs where it went
+func dumpSVGOnFailure(t *testing.T, data []float64, holes []int, dim int, triangles []int) {
+	t.Cleanup(func() {
+		if !t.Failed() {
+			return
+		}
+		dir := os.Getenv("EARCUT_SVG_DIR")
+		if dir == "" {
+			dir = filepath.Join(os.TempDir(), "earcut-go")
+		}
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Log("can't write SVG:", err)
+			return
+		}
+		path := filepath.Join(dir, strings.ReplaceAll(t.Name(), "/", "_")+".svg")
+		var b bytes.Buffer
+		polygon := earcut.Polygon{Data: data, Holes: holes, Dim: dim}
+		err := export.WriteSVG(&b, []earcut.Polygon{polygon}, triangles, export.SVGOptions{Labels: len(data)/dim <= 200, Bridges: true})
+		if err == nil {
+			err = os.WriteFile(path, b.Bytes(), 0o644)
+		}
+		if err != nil {
+			t.Log("can't write SVG:", err)
+			return
+		}
+		t.Log("triangulation drawn in", path)
+	})
+}
+
+// TestFixtures runs mapbox/earcut's fixture suite, with the water, hilbert and issue-numbered
+// shapes among others, together with a few edge cases of our own.
+func TestFixtures(t *testing.T) {
+	runFixtures(t, "fixtures", readExpectations(t, filepath.Join("fixtures", "expected.json")))
+}
+
+func TestFixturesAreListed(t *testing.T) {
+	expected := readExpectations(t, filepath.Join("fixtures", "expected.json"))
+
+	files, err := filepath.Glob(filepath.Join("fixtures", "*.json"))
+	require.NoError(t, err)
+	for _, file := range files {
+		name := filepath.Base(file)
+		name = name[:len(name)-len(".json")]
+		if name == "expected" {
+			continue
+		}
+		assert.Contains(t, expected.Triangles, name, "fixture without expectations")
+	}
+}
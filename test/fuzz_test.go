@@ -0,0 +1,165 @@
+package earcut
+
+import (
+	"encoding/binary"
+	"math"
+	"slices"
+	"testing"
+	"time"
+
+	"earcut-go/pkg/earcut"
+)
+
+// how long a single triangulation may take before it counts as an infinite loop
+const fuzzTimeout = 5 * time.Second
+
+// fuzzPolygon decodes fuzzer bytes into Earcut arguments: coordinates are little-endian int16
+// values times scale, holes are reduced to valid, sorted vertex indices and dim is 2 to 4
+func fuzzPolygon(coords, holes []byte, dim uint8, scale float64) ([]float64, []int, int) {
+	d := 2 + int(dim)%3
+	if scale == 0 || math.IsNaN(scale) || math.IsInf(scale, 0) {
+		scale = 1
+	}
+
+	n := len(coords) / 2 / d
+	data := make([]float64, n*d)
+	for i := range data {
+		data[i] = float64(int16(binary.LittleEndian.Uint16(coords[2*i:]))) * scale
+	}
+
+	var holeIndices []int
+	if n > 1 {
+		for _, b := range holes {
+			holeIndices = append(holeIndices, 1+int(b)%(n-1))
+		}
+		slices.Sort(holeIndices)
+		holeIndices = slices.Compact(holeIndices)
+	}
+	return data, holeIndices, d
+}
+
+// earcutWithin runs EarcutE, failing the test if it doesn't return in time; the error reports
+// invalid input, which is checked before triangulating
+func earcutWithin(t *testing.T, data []float64, holes []int, dim int) ([]int, error) {
+	t.Helper()
+	type result struct {
+		indices []int
+		err     error
+	}
+	done := make(chan result, 1)
+	go func() {
+		indices, err := earcut.EarcutE(data, holes, dim)
+		done <- result{indices, err}
+	}()
+	select {
+	case r := <-done:
+		return r.indices, r.err
+	case <-time.After(fuzzTimeout):
+		t.Fatalf("Earcut did not terminate within %v on data=%v holes=%v dim=%d", fuzzTimeout, data, holes, dim)
+		return nil, nil
+	}
+}
+
+func checkIndices(t *testing.T, indices []int, n int) {
+	t.Helper()
+	if len(indices)%3 != 0 {
+		t.Fatalf("got %d indices, not a multiple of 3", len(indices))
+	}
+	for _, i := range indices {
+		if i < 0 || i >= n {
+			t.Fatalf("index %d out of range [0, %d)", i, n)
+		}
+	}
+}
+
+func FuzzEarcut(f *testing.F) {
+	f.Add([]byte{0, 0, 10, 0, 0, 0, 50, 0, 60, 0, 60, 0, 70, 0, 10, 0}, []byte{}, uint8(0), 1.0)
+
+	f.Fuzz(func(t *testing.T, coords, holes []byte, dim uint8, scale float64) {
+		data, holeIndices, d := fuzzPolygon(coords, holes, dim, scale)
+		indices, err := earcutWithin(t, data, holeIndices, d)
+		if err != nil {
+			// only the overflow of int16 * scale can make the decoded input invalid
+			return
+		}
+		checkIndices(t, indices, len(data)/d)
+
+		// infinite for zero-area input, but never negative or NaN
+		dev := earcut.Deviation(data, holeIndices, d, indices)
+		if math.IsNaN(dev) || dev < 0 {
+			t.Fatalf("deviation %v", dev)
+		}
+		// and close to zero for simple polygons, whichever way their rings wind
+		for _, p := range earcut.Validate(data, holeIndices, d) {
+			if p.Kind != earcut.WrongWinding {
+				return
+			}
+		}
+		if dev > 1e-9 {
+			t.Fatalf("deviation %v on a simple polygon", dev)
+		}
+	})
+}
+
+// FuzzEarcutStar checks that star-shaped polygons, which are always simple, triangulate exactly
+func FuzzEarcutStar(f *testing.F) {
+	f.Add([]byte{10, 20, 10, 20, 10, 20}, 1.0)
+	f.Add([]byte{255, 1, 255, 1, 255, 1, 255, 1}, 1e6)
+
+	f.Fuzz(func(t *testing.T, radii []byte, scale float64) {
+		if len(radii) < 3 || scale <= 0 || scale > 1e12 || math.IsNaN(scale) {
+			return
+		}
+		data := make([]float64, 0, 2*len(radii))
+		for i, r := range radii {
+			a := 2 * math.Pi * float64(i) / float64(len(radii))
+			radius := float64(r) + 1
+			data = append(data, radius*math.Cos(a)*scale, radius*math.Sin(a)*scale)
+		}
+
+		indices, err := earcutWithin(t, data, nil, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkIndices(t, indices, len(radii))
+		if dev := earcut.Deviation(data, nil, 2, indices); dev > 1e-9 {
+			t.Fatalf("deviation %v on a simple polygon", dev)
+		}
+	})
+}
+
+func FuzzFlatten(f *testing.F) {
+	f.Add([]byte{3, 4, 0, 0, 1, 0, 1, 1, 0, 1, 2, 5, 5, 6, 6}, uint8(2))
+	f.Add([]byte{0}, uint8(2))
+
+	f.Fuzz(func(t *testing.T, spec []byte, dim uint8) {
+		// spec is a list of rings, each a vertex count followed by that many vertices
+		d := 2 + int(dim)%2
+		var rings [][][]float64
+		for len(spec) > 0 {
+			n := int(spec[0]) % 16
+			spec = spec[1:]
+			var ring [][]float64
+			for j := 0; j < n && len(spec) >= d; j++ {
+				p := make([]float64, d)
+				for k := range p {
+					p[k] = float64(spec[k])
+				}
+				spec = spec[d:]
+				ring = append(ring, p)
+			}
+			rings = append(rings, ring)
+		}
+
+		data, holes, flatDim := earcut.Flatten(rings)
+		if len(data) == 0 {
+			return
+		}
+		indices, err := earcutWithin(t, data, holes, flatDim)
+		if err != nil {
+			// empty rings produce duplicate or out of range hole indices
+			return
+		}
+		checkIndices(t, indices, len(data)/flatDim)
+	})
+}
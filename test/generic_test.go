@@ -0,0 +1,66 @@
+package earcut
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"earcut-go/pkg/earcut"
+)
+
+func TestEarcutTFloat32Uint16(t *testing.T) {
+	data := []float32{10, 0, 0, 50, 60, 60, 70, 10}
+	indices, err := earcut.EarcutT[float32, uint16](data, nil, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint16{1, 0, 3, 3, 2, 1}, indices)
+}
+
+func TestEarcutTMatchesEarcut(t *testing.T) {
+	data, holes := footprint()
+	expected := earcut.Earcut(data, holes, 2)
+
+	indices, err := earcut.EarcutT[float64, uint32](data, holes, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, len(expected), len(indices))
+	for i := range expected {
+		assert.Equal(t, uint32(expected[i]), indices[i])
+	}
+
+	data32 := make([]float32, len(data))
+	for i, v := range data {
+		data32[i] = float32(v)
+	}
+	ints, err := earcut.EarcutT[float32, int](data32, holes, 2)
+	assert.NoError(t, err)
+	assert.Less(t, earcut.Deviation(data, holes, 2, ints), 1e-6)
+}
+
+func TestEarcutTValidates(t *testing.T) {
+	_, err := earcut.EarcutT[float32, uint32]([]float32{0, 0, 1}, nil, 2)
+	assert.ErrorIs(t, err, earcut.ErrBadDataLength)
+}
+
+func TestEarcutTIndexOverflow(t *testing.T) {
+	fits := circle(1<<16, 0, 0, 1000)
+	indices16, err := earcut.EarcutT[float64, uint16](fits, nil, 2)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, indices16)
+
+	tooMany := append(fits, 0, 0)
+	indices, err := earcut.EarcutT[float64, uint16](tooMany, nil, 2)
+	assert.ErrorIs(t, err, earcut.ErrIndexOverflow)
+	assert.Nil(t, indices)
+}
+
+func TestTriangulateTAppends(t *testing.T) {
+	var tr earcut.Triangulator
+	data := []float32{10, 0, 0, 50, 60, 60, 70, 10}
+
+	dst, err := earcut.TriangulateT(&tr, []uint32{9}, data, nil, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint32{9, 1, 0, 3, 3, 2, 1}, dst)
+
+	dst, err = earcut.TriangulateT(&tr, dst[:0], data, nil, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint32{1, 0, 3, 3, 2, 1}, dst)
+}
@@ -0,0 +1,121 @@
+package earcut
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"earcut-go/pkg/earcut"
+	"earcut-go/pkg/earcut/geojson"
+)
+
+func TestGeoJSONPolygonWithHole(t *testing.T) {
+	doc := `{"type":"Polygon","coordinates":[
+		[[0,0],[100,0],[100,100],[0,100],[0,0]],
+		[[20,20],[20,80],[80,80],[80,20],[20,20]]
+	]}`
+	mesh, err := geojson.Decode(strings.NewReader(doc))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, mesh.Dim)
+	assert.Equal(t, 16, len(mesh.Vertices), "closing duplicates should be dropped")
+	assert.Equal(t, 8*3, len(mesh.Indices))
+	assert.Equal(t, make([]int, 8), mesh.TriangleFeatures)
+	assert.Equal(t, []geojson.Feature{{}}, mesh.Features)
+	assert.Zero(t, earcut.Deviation(mesh.Vertices, []int{4}, 2, mesh.Indices))
+}
+
+func TestGeoJSONMultiPolygonOffsets(t *testing.T) {
+	doc := `{"type":"MultiPolygon","coordinates":[
+		[[[0,0],[1,0],[1,1],[0,0]]],
+		[[[5,5],[6,5],[6,6],[5,6],[5,5]]]
+	]}`
+	mesh, err := geojson.Unmarshal([]byte(doc))
+	assert.NoError(t, err)
+	assert.Equal(t, 14, len(mesh.Vertices))
+	assert.Equal(t, 3*3, len(mesh.Indices))
+	for _, i := range mesh.Indices[3:] {
+		assert.GreaterOrEqual(t, i, 3, "second polygon should index past the first")
+	}
+}
+
+func TestGeoJSONEmptyRings(t *testing.T) {
+	// an empty exterior leaves the polygon out instead of promoting its hole to the shell
+	doc := `{"type":"MultiPolygon","coordinates":[
+		[[], [[20,20],[20,80],[80,80],[80,20],[20,20]]],
+		[[[0,0],[10,0],[10,10],[0,10],[0,0]], [], [[2,2],[2,8],[8,8],[8,2],[2,2]]]
+	]}`
+	mesh, err := geojson.Unmarshal([]byte(doc))
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{0, 0, 10, 0, 10, 10, 0, 10, 2, 2, 2, 8, 8, 8, 8, 2}, mesh.Vertices)
+	assert.InDelta(t, 64, trianglesArea(mesh.Vertices, mesh.Indices), 1e-9)
+
+	mesh, err = geojson.Unmarshal([]byte(`{"type":"Polygon","coordinates":[[], [[0,0],[1,0],[1,1],[0,0]]]}`))
+	assert.NoError(t, err)
+	assert.Empty(t, mesh.Vertices)
+	assert.Empty(t, mesh.Indices)
+}
+
+func TestGeoJSONFeatureCollection(t *testing.T) {
+	doc := `{"type":"FeatureCollection","features":[
+		{"type":"Feature","id":"a","properties":{"name":"square"},
+		 "geometry":{"type":"Polygon","coordinates":[[[0,0,1],[1,0,1],[1,1,1],[0,1,1],[0,0,1]]]}},
+		{"type":"Feature","id":7,"properties":null,"geometry":{"type":"Point","coordinates":[3,3,0]}},
+		{"type":"Feature","id":8,"properties":null,"geometry":null},
+		{"type":"Feature","properties":{},"geometry":{"type":"GeometryCollection","geometries":[
+			{"type":"Polygon","coordinates":[[[5,5,0],[6,5,0],[6,6,0],[5,5,0]]]},
+			{"type":"LineString","coordinates":[[0,0,0],[1,1,0]]}
+		]}}
+	]}`
+	mesh, err := geojson.Unmarshal([]byte(doc))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, mesh.Dim)
+	assert.Equal(t, 7*3, len(mesh.Vertices))
+	assert.Equal(t, []int{0, 0, 3}, mesh.TriangleFeatures)
+	assert.Equal(t, 4, len(mesh.Features))
+	assert.Equal(t, "a", mesh.Features[0].ID)
+	assert.Equal(t, "square", mesh.Features[0].Properties["name"])
+	assert.Equal(t, float64(7), mesh.Features[1].ID)
+}
+
+func TestGeoJSONDecodePolygonsNames(t *testing.T) {
+	doc := `{"type":"FeatureCollection","features":[
+		{"type":"Feature","id":"a","properties":{"name":"square"},
+		 "geometry":{"type":"Polygon","coordinates":[[[0,0],[1,0],[1,1],[0,1],[0,0]]]}},
+		{"type":"Feature","id":7,"properties":{"name":""},"geometry":{"type":"MultiPolygon","coordinates":[
+			[[[0,0],[1,0],[2,0],[0,0]]],
+			[[[5,5],[6,5],[6,6],[5,5]]]
+		]}}
+	]}`
+	polygons, names, err := geojson.DecodePolygons(strings.NewReader(doc))
+	assert.NoError(t, err)
+	assert.Len(t, polygons, 3)
+	assert.Equal(t, []string{"square", "7", "7"}, names)
+
+	// a bare geometry has no name
+	_, names, err = geojson.DecodePolygons(strings.NewReader(`{"type":"Polygon","coordinates":[[[0,0],[1,0],[1,1],[0,0]]]}`))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{""}, names)
+}
+
+func TestGeoJSONErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+		err  error
+	}{
+		{"unknown type", `{"type":"Circle","coordinates":[]}`, geojson.ErrUnknownType},
+		{"bad position", `{"type":"Polygon","coordinates":[[[0],[1,0],[1,1]]]}`, geojson.ErrBadPosition},
+		{"mixed dimensions", `{"type":"Polygon","coordinates":[[[0,0],[1,0,0],[1,1]]]}`, geojson.ErrMixedDimensions},
+		{"feature collection member", `{"type":"FeatureCollection","features":[{"type":"Polygon"}]}`, geojson.ErrUnknownType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := geojson.Unmarshal([]byte(tt.doc))
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+
+	_, err := geojson.Unmarshal([]byte(`{"type":`))
+	assert.Error(t, err)
+}
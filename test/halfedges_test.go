@@ -0,0 +1,76 @@
+package earcut
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"earcut-go/pkg/earcut"
+)
+
+func TestHalfedges(t *testing.T) {
+	data := append(circle(16, 0, 0, 10), circle(8, 2, 1, 3)...)
+	holes := []int{16}
+	triangles := earcut.Earcut(data, holes, 2)
+	require.NotEmpty(t, triangles)
+
+	halfedges := earcut.Halfedges(triangles)
+	require.Len(t, halfedges, len(triangles))
+	boundary := 0
+	for e, twin := range halfedges {
+		if twin < 0 {
+			boundary++
+			continue
+		}
+		assert.Equal(t, e, halfedges[twin])
+		assert.Equal(t, triangles[e], triangles[earcut.NextHalfedge(twin)])
+		assert.Equal(t, triangles[earcut.NextHalfedge(e)], triangles[twin])
+	}
+	// only the ring edges are on the boundary
+	assert.Equal(t, 24, boundary)
+
+	neighbors := earcut.Neighbors(triangles)
+	for e, n := range neighbors {
+		if halfedges[e] < 0 {
+			assert.Equal(t, -1, n)
+		} else {
+			assert.Equal(t, halfedges[e]/3, n)
+		}
+	}
+
+	// a flood fill over the neighbors reaches every triangle
+	reached := map[int]bool{0: true}
+	stack := []int{0}
+	for len(stack) > 0 {
+		tri := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for k := 0; k < 3; k++ {
+			if n := neighbors[3*tri+k]; n >= 0 && !reached[n] {
+				reached[n] = true
+				stack = append(stack, n)
+			}
+		}
+	}
+	assert.Len(t, reached, len(triangles)/3)
+}
+
+func TestBoundary(t *testing.T) {
+	data := append(circle(16, 0, 0, 10), circle(8, 2, 1, 3)...)
+	holes := []int{16}
+	triangles := earcut.Earcut(data, holes, 2)
+	loops := earcut.Boundary(triangles, earcut.Halfedges(triangles))
+	require.Len(t, loops, 2)
+
+	sizes := []int{len(loops[0]), len(loops[1])}
+	assert.ElementsMatch(t, []int{16, 8}, sizes)
+	for _, loop := range loops {
+		// consecutive half-edges share a vertex and the loop closes
+		for k, e := range loop {
+			next := loop[(k+1)%len(loop)]
+			assert.Equal(t, triangles[earcut.NextHalfedge(e)], triangles[next])
+		}
+	}
+
+	assert.Empty(t, earcut.Boundary(nil, nil))
+}
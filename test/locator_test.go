@@ -0,0 +1,91 @@
+package earcut
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"earcut-go/pkg/earcut"
+)
+
+// whether (x, y) lies inside the polygon by the even-odd rule over all its rings
+func insideRings(data []float64, holes []int, x, y float64) bool {
+	inside := false
+	starts := append([]int{0}, holes...)
+	for r, start := range starts {
+		end := len(data) / 2
+		if r+1 < len(starts) {
+			end = starts[r+1]
+		}
+		for i, j := start, end-1; i < end; j, i = i, i+1 {
+			xi, yi, xj, yj := data[2*i], data[2*i+1], data[2*j], data[2*j+1]
+			if (yi > y) != (yj > y) && x < (xj-xi)*(y-yi)/(yj-yi)+xi {
+				inside = !inside
+			}
+		}
+	}
+	return inside
+}
+
+func TestLocator(t *testing.T) {
+	data := append(circle(64, 0, 0, 10), circle(16, 3, -2, 4)...)
+	holes := []int{64}
+	triangles := earcut.Earcut(data, holes, 2)
+	locator := earcut.NewLocator(data, 2, triangles)
+
+	rng := rand.New(rand.NewSource(1))
+	for k := 0; k < 5000; k++ {
+		x, y := rng.Float64()*24-12, rng.Float64()*24-12
+		tri, bary, ok := locator.Locate(x, y)
+		require.Equal(t, insideRings(data, holes, x, y), ok, "point %v, %v", x, y)
+		if !ok {
+			assert.Equal(t, -1, tri)
+			continue
+		}
+
+		// the coordinates are non-negative, sum to 1 and give back the point
+		var px, py, sum float64
+		for i, w := range bary {
+			v := triangles[3*tri+i]
+			assert.GreaterOrEqual(t, w, 0.0)
+			px += w * data[2*v]
+			py += w * data[2*v+1]
+			sum += w
+		}
+		assert.InDelta(t, 1, sum, 1e-12)
+		assert.InDelta(t, x, px, 1e-9)
+		assert.InDelta(t, y, py, 1e-9)
+	}
+}
+
+func TestLocatorVertices(t *testing.T) {
+	data := []float64{0, 0, 0, 0, 4, 0, 4, 4, 5, 5, 0, 4}
+	triangles := earcut.Earcut(data, nil, 3)
+	locator := earcut.NewLocator(data, 3, triangles)
+
+	// vertices and edge midpoints are inside
+	for i := 0; i < len(data); i += 3 {
+		assert.True(t, locator.Contains(data[i], data[i+1]))
+	}
+	assert.True(t, locator.Contains(2, 0))
+	assert.True(t, locator.Contains(2, 4))
+	assert.False(t, locator.Contains(2, 4.001))
+	assert.False(t, locator.Contains(-1, 2))
+
+	empty := earcut.NewLocator(nil, 2, nil)
+	assert.False(t, empty.Contains(0, 0))
+}
+
+func BenchmarkLocator(b *testing.B) {
+	data := append(circle(1000, 0, 0, 10), circle(200, 3, -2, 4)...)
+	triangles := earcut.Earcut(data, []int{1000}, 2)
+	locator := earcut.NewLocator(data, 2, triangles)
+	rng := rand.New(rand.NewSource(1))
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		locator.Locate(rng.Float64()*24-12, rng.Float64()*24-12)
+	}
+}
@@ -0,0 +1,70 @@
+package earcut
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"earcut-go/pkg/earcut"
+)
+
+func circle(n int, cx, cy, r float64) []float64 {
+	data := make([]float64, 0, 2*n)
+	for i := 0; i < n; i++ {
+		a := 2 * math.Pi * float64(i) / float64(n)
+		data = append(data, cx+r*math.Cos(a), cy+r*math.Sin(a))
+	}
+	return data
+}
+
+func TestOptionsZeroValueMatchesEarcut(t *testing.T) {
+	data := append(circle(120, 0, 0, 100), circle(30, 10, 10, 20)...)
+	holes := []int{120}
+	assert.Equal(t, earcut.Earcut(data, holes, 2), earcut.EarcutWithOptions(data, holes, 2, earcut.Options{}))
+}
+
+func TestOptionsHashing(t *testing.T) {
+	data := append(circle(120, 0, 0, 100), circle(30, 10, 10, 20)...)
+	holes := []int{120}
+
+	for _, mode := range []earcut.HashMode{earcut.HashAuto, earcut.HashAlways, earcut.HashNever} {
+		indices := earcut.EarcutWithOptions(data, holes, 2, earcut.Options{Hashing: mode})
+		assert.Equal(t, 150*3, len(indices))
+		assert.Less(t, earcut.Deviation(data, holes, 2, indices), 1e-9)
+	}
+
+	small := circle(20, 0, 0, 10)
+	assert.Equal(t,
+		earcut.EarcutWithOptions(small, nil, 2, earcut.Options{Hashing: earcut.HashAlways}),
+		earcut.EarcutWithOptions(small, nil, 2, earcut.Options{HashThreshold: 3}))
+	assert.Equal(t,
+		earcut.EarcutWithOptions(data, holes, 2, earcut.Options{Hashing: earcut.HashNever}),
+		earcut.EarcutWithOptions(data, holes, 2, earcut.Options{HashThreshold: 1000}))
+}
+
+func TestOptionsPasses(t *testing.T) {
+	// self-intersecting ring that only the cure and split passes fully triangulate
+	data := []float64{3, 3, 0, 1, 5, 5, 5, 4, 1, 3, 4, 0, 2, 2, 4, 5}
+
+	tests := []struct {
+		name      string
+		opts      earcut.Options
+		triangles int
+	}{
+		{"ears only", earcut.Options{MaxPass: earcut.PassEars}, 3},
+		{"filter", earcut.Options{MaxPass: earcut.PassFilter}, 3},
+		{"cure", earcut.Options{MaxPass: earcut.PassCure}, 4},
+		{"cure disabled", earcut.Options{MaxPass: earcut.PassCure, DisableCure: true}, 3},
+		{"all", earcut.Options{}, 4},
+		{"negative", earcut.Options{MaxPass: -1}, 4},
+		{"too large", earcut.Options{MaxPass: earcut.PassSplit + 1}, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			indices := earcut.EarcutWithOptions(data, nil, 2, tt.opts)
+			assert.Equal(t, tt.triangles*3, len(indices))
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package earcut
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"earcut-go/pkg/earcut"
+)
+
+var planeOpts = earcut.Options{ProjectToPlane: true}
+
+// lift 2D data into 3D: x along (ux, uy, uz), y along (vx, vy, vz), offset by origin
+func lift(data []float64, origin, u, v [3]float64) []float64 {
+	out := make([]float64, 0, len(data)/2*3)
+	for i := 0; i < len(data); i += 2 {
+		for k := 0; k < 3; k++ {
+			out = append(out, origin[k]+data[i]*u[k]+data[i+1]*v[k])
+		}
+	}
+	return out
+}
+
+// vector area (half the cross product) of each triangle
+func triangleNormals(data []float64, indices []int) [][3]float64 {
+	var normals [][3]float64
+	for t := 0; t < len(indices); t += 3 {
+		a, b, c := data[indices[t]*3:], data[indices[t+1]*3:], data[indices[t+2]*3:]
+		e1 := [3]float64{b[0] - a[0], b[1] - a[1], b[2] - a[2]}
+		e2 := [3]float64{c[0] - a[0], c[1] - a[1], c[2] - a[2]}
+		normals = append(normals, [3]float64{
+			(e1[1]*e2[2] - e1[2]*e2[1]) / 2,
+			(e1[2]*e2[0] - e1[0]*e2[2]) / 2,
+			(e1[0]*e2[1] - e1[1]*e2[0]) / 2,
+		})
+	}
+	return normals
+}
+
+func TestProjectToPlaneWall(t *testing.T) {
+	// a 10×5 wall in the xz plane with a 2×2 window; it has zero area seen from above
+	wall := []float64{
+		0, 0, 0, 10, 0, 0, 10, 0, 5, 0, 0, 5,
+		2, 0, 1, 2, 0, 3, 4, 0, 3, 4, 0, 1,
+	}
+	holes := []int{4}
+
+	assert.Empty(t, earcut.Earcut(wall, holes, 3))
+
+	indices := earcut.EarcutWithOptions(wall, holes, 3, planeOpts)
+	assert.Equal(t, 8*3, len(indices))
+
+	// the outer ring winds counterclockwise around -y, and so does every triangle
+	area := 0.0
+	for _, n := range triangleNormals(wall, indices) {
+		assert.InDelta(t, 0, n[0], 1e-12)
+		assert.InDelta(t, 0, n[2], 1e-12)
+		assert.Less(t, n[1], 0.0)
+		area -= n[1]
+	}
+	assert.InDelta(t, 50-4, area, 1e-9)
+}
+
+func TestProjectToPlaneTilted(t *testing.T) {
+	data := append(circle(60, 0, 0, 10), circle(20, 2, 1, 3)...)
+	holes := []int{60}
+	flat := earcut.Earcut(data, holes, 2)
+
+	s := math.Sqrt(0.5)
+	roof := lift(data, [3]float64{1000, 2000, 30}, [3]float64{s, s, 0}, [3]float64{-0.5, 0.5, s})
+	indices := earcut.EarcutWithOptions(roof, holes, 3, planeOpts)
+	assert.Equal(t, len(flat), len(indices))
+
+	// triangles tile the roof: their areas add up to that of the flat polygon
+	area := 0.0
+	for _, n := range triangleNormals(roof, indices) {
+		area += math.Sqrt(n[0]*n[0] + n[1]*n[1] + n[2]*n[2])
+	}
+	assert.InDelta(t, area2D(data, flat), area, 1e-6)
+}
+
+func TestProjectToPlaneFlat(t *testing.T) {
+	// counterclockwise rings in the xy plane triangulate exactly as without projection
+	data := append(circle(100, 0, 0, 10), circle(10, 1, 1, 2)...)
+	flat := lift(data, [3]float64{0, 0, 7}, [3]float64{1, 0, 0}, [3]float64{0, 1, 0})
+	holes := []int{100}
+	assert.Equal(t, earcut.Earcut(flat, holes, 3), earcut.EarcutWithOptions(flat, holes, 3, planeOpts))
+
+	// and 2D input ignores the option
+	assert.Equal(t, earcut.Earcut(data, holes, 2), earcut.EarcutWithOptions(data, holes, 2, planeOpts))
+}
+
+func TestProjectToPlaneTriangulator(t *testing.T) {
+	wall := []float64{0, 0, 0, 0, 10, 0, 0, 10, 5, 0, 0, 5}
+	tr := earcut.Triangulator{Options: planeOpts}
+	assert.Equal(t, 6, len(tr.Triangulate(nil, wall, nil, 3)))
+
+	indices, err := earcut.TriangulateT(&tr, []uint16{}, []float32{0, 0, 0, 0, 10, 0, 0, 10, 5, 0, 0, 5}, nil, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, 6, len(indices))
+}
+
+func area2D(data []float64, indices []int) float64 {
+	area := 0.0
+	for t := 0; t < len(indices); t += 3 {
+		a, b, c := data[indices[t]*2:], data[indices[t+1]*2:], data[indices[t+2]*2:]
+		area += math.Abs((b[0]-a[0])*(c[1]-a[1])-(c[0]-a[0])*(b[1]-a[1])) / 2
+	}
+	return area
+}
@@ -0,0 +1,111 @@
+package earcut
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"earcut-go/pkg/earcut"
+)
+
+// largest triangle area in the mesh
+func maxTriangleArea(data []float64, dim int, triangles []int) float64 {
+	largest := 0.0
+	for t := 0; t < len(triangles); t += 3 {
+		a, b, c := triangles[t]*dim, triangles[t+1]*dim, triangles[t+2]*dim
+		area := math.Abs((data[b]-data[a])*(data[c+1]-data[a+1])-(data[c]-data[a])*(data[b+1]-data[a+1])) / 2
+		largest = max(largest, area)
+	}
+	return largest
+}
+
+// polygon area from its rings
+func ringsArea(data []float64, holes []int) float64 {
+	area := 0.0
+	starts := append([]int{0}, holes...)
+	for r, start := range starts {
+		end := len(data) / 2
+		if r+1 < len(starts) {
+			end = starts[r+1]
+		}
+		sum := 0.0
+		for i, j := start, end-1; i < end; j, i = i, i+1 {
+			sum += data[2*j]*data[2*i+1] - data[2*i]*data[2*j+1]
+		}
+		if r == 0 {
+			area += math.Abs(sum) / 2
+		} else {
+			area -= math.Abs(sum) / 2
+		}
+	}
+	return area
+}
+
+func TestRefineQuality(t *testing.T) {
+	shapes := []struct {
+		name  string
+		data  []float64
+		holes []int
+	}{
+		{"square", []float64{0, 0, 10, 0, 10, 10, 0, 10}, nil},
+		{"strip", strip(20), nil},
+		{"ring", append(circle(40, 0, 0, 10), circle(12, 1, -2, 4)...), []int{40}},
+		{"holes", []float64{0, 0, 100, 0, 100, 100, 0, 100, 20, 20, 40, 20, 40, 40, 20, 40, 60, 60, 80, 60, 70, 80}, []int{4, 8}},
+	}
+
+	for _, s := range shapes {
+		t.Run(s.name, func(t *testing.T) {
+			opts := earcut.QualityOptions{MinAngle: 25, MaxArea: ringsArea(s.data, s.holes) / 50}
+			vertices, triangles := earcut.RefineQuality(s.data, s.holes, 2, opts)
+
+			assert.Equal(t, s.data, vertices[:len(s.data)], "input vertices keep their indices")
+			assert.Greater(t, len(vertices), len(s.data))
+			checkIndices(t, triangles, len(vertices)/2)
+
+			assert.GreaterOrEqual(t, earcut.MinAngle(vertices, 2, triangles), 25.0)
+			assert.LessOrEqual(t, maxTriangleArea(vertices, 2, triangles), opts.MaxArea)
+			assert.InDelta(t, ringsArea(s.data, s.holes), area2D(vertices, triangles), 1e-9*ringsArea(s.data, s.holes))
+		})
+	}
+}
+
+func TestRefineQualityAreaOnly(t *testing.T) {
+	data := []float64{0, 0, 10, 0, 10, 10, 0, 10}
+	vertices, triangles := earcut.RefineQuality(data, nil, 2, earcut.QualityOptions{MaxArea: 0.5})
+	assert.GreaterOrEqual(t, len(triangles)/3, 200)
+	assert.LessOrEqual(t, maxTriangleArea(vertices, 2, triangles), 0.5)
+	assert.InDelta(t, 100, area2D(vertices, triangles), 1e-9)
+}
+
+func TestRefineQualityInterpolates(t *testing.T) {
+	// a tilted plane z = x + 2y: new vertices must lie on it
+	var data []float64
+	for i := 0; i < len(strip(10)); i += 2 {
+		x, y := strip(10)[i], strip(10)[i+1]*5
+		data = append(data, x, y, x+2*y)
+	}
+	vertices, triangles := earcut.RefineQuality(data, nil, 3, earcut.QualityOptions{MinAngle: 20, MaxArea: 1})
+	assert.Greater(t, len(vertices), len(data))
+	checkIndices(t, triangles, len(vertices)/3)
+	for i := len(data); i < len(vertices); i += 3 {
+		assert.InDelta(t, vertices[i]+2*vertices[i+1], vertices[i+2], 1e-9)
+	}
+}
+
+func TestRefineQualityMaxSteiner(t *testing.T) {
+	data := []float64{0, 0, 10, 0, 10, 10, 0, 10}
+	vertices, triangles := earcut.RefineQuality(data, nil, 2, earcut.QualityOptions{MaxArea: 1e-6, MaxSteiner: 50})
+	assert.Equal(t, len(data)+50*2, len(vertices))
+	assert.InDelta(t, 100, area2D(vertices, triangles), 1e-9)
+
+	// sharp input corners can't all be fixed, but refinement still ends
+	spike := []float64{0, 0, 10, 0, 10, 0.3, 0, 0.1}
+	vertices, triangles = earcut.RefineQuality(spike, nil, 2, earcut.QualityOptions{MinAngle: 30})
+	checkIndices(t, triangles, len(vertices)/2)
+	assert.InDelta(t, ringsArea(spike, nil), area2D(vertices, triangles), 1e-9)
+
+	vertices, triangles = earcut.RefineQuality(nil, nil, 2, earcut.QualityOptions{MinAngle: 20})
+	assert.Empty(t, vertices)
+	assert.Empty(t, triangles)
+}
@@ -0,0 +1,97 @@
+package earcut
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"earcut-go/pkg/earcut"
+)
+
+// an axis-aligned square ring, counter-clockwise unless cw is set
+func square(x0, y0, size float64, cw bool) []float64 {
+	ring := []float64{x0, y0, x0 + size, y0, x0 + size, y0 + size, x0, y0 + size}
+	if cw {
+		ring = []float64{x0, y0, x0, y0 + size, x0 + size, y0 + size, x0 + size, y0}
+	}
+	return ring
+}
+
+// total area of the triangles
+func trianglesArea(data []float64, triangles []int) float64 {
+	area := 0.0
+	for t := 0; t < len(triangles); t += 3 {
+		a, b, c := 2*triangles[t], 2*triangles[t+1], 2*triangles[t+2]
+		area += math.Abs((data[b]-data[a])*(data[c+1]-data[a+1])-(data[c]-data[a])*(data[b+1]-data[a+1])) / 2
+	}
+	return area
+}
+
+func TestClassifyRings(t *testing.T) {
+	island := square(4, 4, 2, false)
+	outer := square(0, 0, 10, false)
+	lake := square(2, 2, 6, true)
+	lakeCCW := square(2, 2, 6, false)
+	other := square(20, 0, 5, true)
+	spike := []float64{30, 0, 31, 0, 30, 0}
+
+	tests := []struct {
+		name  string
+		rings [][]float64
+		rule  earcut.FillRule
+		want  [][]int
+		area  float64
+	}{
+		{"even-odd nested", [][]float64{island, outer, lake}, earcut.EvenOdd, [][]int{{1, 2}, {0}}, 100 - 36 + 4},
+		{"even-odd ignores winding", [][]float64{island, outer, lakeCCW}, earcut.EvenOdd, [][]int{{1, 2}, {0}}, 68},
+		{"nonzero nested", [][]float64{island, outer, lake}, earcut.NonZero, [][]int{{1, 2}, {0}}, 68},
+		{"nonzero same winding", [][]float64{island, outer, lakeCCW}, earcut.NonZero, [][]int{{1}}, 100},
+		{"separate shells", [][]float64{other, spike, outer, lake}, earcut.EvenOdd, [][]int{{2, 3}, {0}}, 100 - 36 + 25},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, earcut.ClassifyRings(tt.rings, 2, tt.rule))
+
+			vertices, triangles := earcut.EarcutRings(tt.rings, 2, tt.rule)
+			assert.Equal(t, slices.Concat(tt.rings...), vertices)
+			assert.InDelta(t, tt.area, trianglesArea(vertices, triangles), 1e-9)
+		})
+	}
+}
+
+func TestClassifyRingsTouching(t *testing.T) {
+	// a diamond hole whose vertices all sit on the sides of the square
+	outer := square(0, 0, 4, false)
+	diamond := []float64{2, 0, 4, 2, 2, 4, 0, 2}
+	assert.Equal(t, [][]int{{1, 0}}, earcut.ClassifyRings([][]float64{diamond, outer}, 2, earcut.EvenOdd))
+
+	vertices, triangles := earcut.EarcutRings([][]float64{diamond, outer}, 2, earcut.EvenOdd)
+	assert.InDelta(t, 8, trianglesArea(vertices, triangles), 1e-9)
+}
+
+func TestEarcutRingsValidates(t *testing.T) {
+	bad := []float64{0, 0, 10, 0, 10, 10, 0}
+	rings := [][]float64{square(0, 0, 4, false), bad}
+
+	_, err := earcut.ClassifyRingsE(rings, 2, earcut.EvenOdd)
+	assert.ErrorIs(t, err, earcut.ErrBadDataLength)
+	assert.ErrorContains(t, err, "ring 1")
+	_, _, err = earcut.EarcutRingsE(rings, 2, earcut.EvenOdd)
+	assert.ErrorIs(t, err, earcut.ErrBadDataLength)
+	_, _, err = earcut.EarcutRingsE([][]float64{{0, 0, 1, 0, math.NaN(), 1}}, 2, earcut.NonZero)
+	assert.ErrorIs(t, err, earcut.ErrNonFiniteCoordinate)
+	_, err = earcut.ClassifyRingsE(rings, 1, earcut.EvenOdd)
+	assert.ErrorIs(t, err, earcut.ErrBadDimension)
+
+	// the variants without an error give up instead of panicking
+	assert.Nil(t, earcut.ClassifyRings(rings, 2, earcut.EvenOdd))
+	vertices, triangles := earcut.EarcutRings(rings, 2, earcut.EvenOdd)
+	assert.Nil(t, vertices)
+	assert.Nil(t, triangles)
+
+	vertices, triangles, err = earcut.EarcutRingsE(rings[:1], 2, earcut.EvenOdd)
+	assert.NoError(t, err)
+	assert.InDelta(t, 16, trianglesArea(vertices, triangles), 1e-9)
+}
@@ -0,0 +1,126 @@
+package earcut
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"earcut-go/pkg/earcut"
+)
+
+func rat(f float64) *big.Rat {
+	return new(big.Rat).SetFloat64(f)
+}
+
+// exact (b - a) × (c - a)
+func exactCross(ax, ay, bx, by, cx, cy float64) *big.Rat {
+	l := new(big.Rat).Mul(new(big.Rat).Sub(rat(bx), rat(ax)), new(big.Rat).Sub(rat(cy), rat(ay)))
+	r := new(big.Rat).Mul(new(big.Rat).Sub(rat(by), rat(ay)), new(big.Rat).Sub(rat(cx), rat(ax)))
+	return l.Sub(l, r)
+}
+
+// twice the area covered by the triangles, counting overlaps twice, computed exactly
+func exactTrianglesArea(data []float64, indices []int) *big.Rat {
+	sum := new(big.Rat)
+	for t := 0; t < len(indices); t += 3 {
+		a, b, c := indices[t]*2, indices[t+1]*2, indices[t+2]*2
+		sum.Add(sum, new(big.Rat).Abs(exactCross(data[a], data[a+1], data[b], data[b+1], data[c], data[c+1])))
+	}
+	return sum
+}
+
+// twice the area of a single ring, computed exactly
+func exactRingArea(data []float64) *big.Rat {
+	sum := new(big.Rat)
+	for i := 2; i+2 < len(data); i += 2 {
+		sum.Add(sum, exactCross(data[0], data[1], data[i], data[i+1], data[i+2], data[i+3]))
+	}
+	return sum.Abs(sum)
+}
+
+func TestOrient2D(t *testing.T) {
+	assert.Greater(t, earcut.Orient2D(0, 0, 1, 0, 0, 1), 0.0)
+	assert.Less(t, earcut.Orient2D(0, 0, 0, 1, 1, 0), 0.0)
+	assert.Equal(t, 0.0, earcut.Orient2D(0, 0, 1, 1, 2, 2))
+
+	// points a few ulps around (0.5, 0.5) against the line y = x, the classic failure case
+	const ulp = 1.0 / (1 << 53)
+	plainWrong := 0
+	for i := 0; i < 64; i++ {
+		for j := 0; j < 64; j++ {
+			ax, ay := 0.5+float64(i)*ulp, 0.5+float64(j)*ulp
+			want := exactCross(ax, ay, 12, 12, 24, 24).Sign()
+
+			assert.Equal(t, want, sign(earcut.Orient2D(ax, ay, 12, 12, 24, 24)), "a = (%v, %v)", ax, ay)
+			if sign((12-ax)*(24-ay)-(12-ay)*(24-ax)) != want {
+				plainWrong++
+			}
+		}
+	}
+	assert.Greater(t, plainWrong, 0)
+}
+
+func sign(v float64) int {
+	switch {
+	case v > 0:
+		return 1
+	case v < 0:
+		return -1
+	}
+	return 0
+}
+
+// thin simple polygons at Web Mercator scale whose vertices are a few ulps off a straight line
+var nearDegenerate = []struct {
+	name string
+	data []float64
+}{
+	{"sliver", []float64{
+		1.0965385768406622e+07, 5.826827972493259e+06, 1.0965435768406622e+07, 5.826977972493259e+06,
+		1.0965485768406622e+07, 5.827127972493258e+06, 1.0965435768406622e+07, 5.82697797249326e+06,
+	}},
+	{"sliver dropped", []float64{
+		1.0178907687826827e+07, 5.877178599184338e+06, 1.0178957687826827e+07, 5.877328599184337e+06,
+		1.0179007687826827e+07, 5.877478599184338e+06, 1.0178957687826827e+07, 5.877328599184339e+06,
+	}},
+	{"overlapping", []float64{
+		1.0144069672634182e+07, 5.219069850377654e+06, 1.0144103005967516e+07, 5.219103183710987e+06,
+		1.0144136339300849e+07, 5.219136517044321e+06, 1.0144169672634182e+07, 5.219169850377652e+06,
+		1.0144136339300849e+07, 5.219136517044322e+06, 1.0144103005967516e+07, 5.219103183710988e+06,
+	}},
+	{"wedge", []float64{
+		1.0834349990993535e+07, 5.660771762018859e+06, 1.0834374990993537e+07, 5.660781012018858e+06,
+		1.0834399990993535e+07, 5.660790262018859e+06, 1.0834424990993535e+07, 5.660799512018858e+06,
+		1.0834449990993533e+07, 5.660808762018858e+06, 1.0834399990993537e+07, 5.66079026201886e+06,
+	}},
+}
+
+func TestRobustNearDegenerate(t *testing.T) {
+	for _, tt := range nearDegenerate {
+		t.Run(tt.name, func(t *testing.T) {
+			area := exactRingArea(tt.data)
+
+			plain := earcut.Earcut(tt.data, nil, 2)
+			assert.NotEqual(t, 0, exactTrianglesArea(tt.data, plain).Cmp(area), "plain arithmetic used to get this right")
+
+			robust := earcut.EarcutWithOptions(tt.data, nil, 2, earcut.Options{Robust: true})
+			assert.Equal(t, len(tt.data)/2-2, len(robust)/3)
+			assert.Equal(t, 0, exactTrianglesArea(tt.data, robust).Cmp(area), "triangles %v", robust)
+		})
+	}
+}
+
+func TestRobustMatchesPlain(t *testing.T) {
+	// away from degeneracies both modes see the same signs and so cut the same ears
+	for _, name := range []string{"building", "steiner", "bad-hole", "issue45"} {
+		data, holes, dim := loadFixture(t, name)
+		assert.Equal(t, earcut.Earcut(data, holes, dim),
+			earcut.EarcutWithOptions(data, holes, dim, earcut.Options{Robust: true}), name)
+	}
+
+	data := append(circle(500, 0, 0, 1e7), circle(100, 1e6, 0, 1e5)...)
+	robust := earcut.EarcutWithOptions(data, []int{500}, 2, earcut.Options{Robust: true})
+	assert.Equal(t, earcut.Earcut(data, []int{500}, 2), robust)
+	assert.Less(t, earcut.Deviation(data, []int{500}, 2, robust), 1e-9)
+}
@@ -0,0 +1,66 @@
+package earcut
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"earcut-go/pkg/earcut"
+)
+
+// the triangles rotated to start at their lowest vertex, which keeps the winding, and sorted
+func canonicalTriangles(triangles []int) [][3]int {
+	out := make([][3]int, 0, len(triangles)/3)
+	for t := 0; t < len(triangles); t += 3 {
+		a, b, c := triangles[t], triangles[t+1], triangles[t+2]
+		for a > b || a > c {
+			a, b, c = b, c, a
+		}
+		out = append(out, [3]int{a, b, c})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		for k := 0; k < 3; k++ {
+			if out[i][k] != out[j][k] {
+				return out[i][k] < out[j][k]
+			}
+		}
+		return false
+	})
+	return out
+}
+
+func TestStrips(t *testing.T) {
+	for _, name := range []string{"building", "issue45", "steiner", "touching2", "circle"} {
+		t.Run(name, func(t *testing.T) {
+			var triangles []int
+			if name == "circle" {
+				data := append(circle(500, 0, 0, 10), circle(40, 3, -2, 4)...)
+				triangles = earcut.Earcut(data, []int{500}, 2)
+			} else {
+				data, holes, dim := loadFixture(t, name)
+				triangles = earcut.Earcut(data, holes, dim)
+			}
+			want := canonicalTriangles(triangles)
+
+			strips := earcut.Strips(triangles)
+			restarted := earcut.RestartStrips(strips, -1)
+			assert.Equal(t, want, canonicalTriangles(earcut.StripTriangles(restarted, -1)))
+
+			stitched := earcut.StitchStrips(strips)
+			assert.Equal(t, want, canonicalTriangles(earcut.StripTriangles(stitched, -2)))
+
+			// strips need fewer indices than the list they replace
+			assert.Less(t, len(restarted), len(triangles))
+		})
+	}
+}
+
+func TestStripsEmpty(t *testing.T) {
+	assert.Empty(t, earcut.Strips(nil))
+	assert.Empty(t, earcut.StitchStrips(nil))
+	assert.Equal(t, []int{0, 1, 2}, earcut.StitchStrips([][]int{{}, {0, 1, 2}}))
+	assert.Equal(t, []int{0, 1, 2, 2, 3, 3, 3, 4, 5}, earcut.StitchStrips([][]int{{0, 1, 2}, nil, {3, 4, 5}}))
+	assert.Empty(t, earcut.StripTriangles(nil, 0xffff))
+	assert.Equal(t, []int{0, 1, 2, 0xffff, 3, 4, 5}, earcut.RestartStrips([][]int{{0, 1, 2}, {3, 4, 5}}, 0xffff))
+}
@@ -0,0 +1,131 @@
+package earcut
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"earcut-go/pkg/earcut"
+)
+
+// stroke runs earcut.Stroke and checks that the result is well formed, with every triangle
+// counter-clockwise
+func stroke(t *testing.T, points []float64, opts earcut.StrokeOptions) (vertices []float64, triangles []int) {
+	t.Helper()
+	vertices, triangles = earcut.Stroke(points, 2, opts)
+	checkIndices(t, triangles, len(vertices)/2)
+	for i := 0; i < len(triangles); i += 3 {
+		a, b, c := 2*triangles[i], 2*triangles[i+1], 2*triangles[i+2]
+		area := (vertices[b]-vertices[a])*(vertices[c+1]-vertices[a+1]) - (vertices[b+1]-vertices[a+1])*(vertices[c]-vertices[a])
+		require.Greater(t, area, 0.0, "triangle %d", i/3)
+	}
+	return vertices, triangles
+}
+
+// whether any triangle covers the point
+func covered(vertices []float64, triangles []int, x, y float64) bool {
+	for i := 0; i < len(triangles); i += 3 {
+		inside := true
+		for k := 0; k < 3; k++ {
+			a, b := 2*triangles[i+k], 2*triangles[i+(k+1)%3]
+			if (vertices[b]-vertices[a])*(y-vertices[a+1])-(vertices[b+1]-vertices[a+1])*(x-vertices[a]) < 0 {
+				inside = false
+			}
+		}
+		if inside {
+			return true
+		}
+	}
+	return false
+}
+
+func TestStrokeCaps(t *testing.T) {
+	line := []float64{0, 0, 10, 0}
+	vertices, triangles := stroke(t, line, earcut.StrokeOptions{Width: 2})
+	assert.Len(t, vertices, 8)
+	assert.InDelta(t, 20, trianglesArea(vertices, triangles), 1e-9)
+
+	vertices, triangles = stroke(t, line, earcut.StrokeOptions{Width: 2, Cap: earcut.SquareCap})
+	assert.InDelta(t, 24, trianglesArea(vertices, triangles), 1e-9)
+	assert.True(t, covered(vertices, triangles, -0.9, 0.9))
+	assert.True(t, covered(vertices, triangles, 10.9, -0.9))
+
+	// round caps add a disc of radius 1, short of it by the chords
+	opts := earcut.StrokeOptions{Width: 2, Cap: earcut.RoundCap, Tolerance: 0.01}
+	vertices, triangles = stroke(t, line, opts)
+	area := trianglesArea(vertices, triangles)
+	assert.LessOrEqual(t, area, 20+math.Pi)
+	assert.Greater(t, area, 20+math.Pi-2*math.Pi*opts.Tolerance)
+	assert.True(t, covered(vertices, triangles, -0.95, 0))
+	assert.False(t, covered(vertices, triangles, -0.7, 0.75))
+
+	// a lone point only draws its caps
+	vertices, triangles = stroke(t, []float64{3, 3, 3, 3}, opts)
+	assert.InDelta(t, math.Pi, trianglesArea(vertices, triangles), 2*math.Pi*opts.Tolerance)
+	vertices, _ = stroke(t, []float64{3, 3}, earcut.StrokeOptions{Width: 2})
+	assert.Empty(t, vertices)
+
+	vertices, _ = stroke(t, line, earcut.StrokeOptions{})
+	assert.Empty(t, vertices)
+
+	// a tolerance far below the width still rounds the caps, with a bounded number of chords
+	vertices, triangles = stroke(t, line, earcut.StrokeOptions{Width: 2, Cap: earcut.RoundCap, Tolerance: 1e-18})
+	assert.InDelta(t, 20+math.Pi, trianglesArea(vertices, triangles), 1e-6)
+	assert.Less(t, len(vertices)/2, 2*(1<<16)+10)
+	assert.True(t, covered(vertices, triangles, -0.999, 0))
+}
+
+func TestStrokeJoins(t *testing.T) {
+	// a left turn, with the outside of the corner at (10, 0) towards (11, -1)
+	turn := []float64{0, 0, 10, 0, 10, 10}
+	for _, tc := range []struct {
+		join              earcut.LineJoin
+		tip, round, bevel bool
+	}{
+		{join: earcut.MiterJoin, tip: true, round: true, bevel: true},
+		{join: earcut.RoundJoin, round: true, bevel: true},
+		{join: earcut.BevelJoin, bevel: true},
+	} {
+		vertices, triangles := stroke(t, turn, earcut.StrokeOptions{Width: 2, Join: tc.join})
+		assert.Equal(t, tc.tip, covered(vertices, triangles, 10.95, -0.95), "join %d", tc.join)
+		assert.Equal(t, tc.round, covered(vertices, triangles, 10.6, -0.6), "join %d", tc.join)
+		assert.Equal(t, tc.bevel, covered(vertices, triangles, 10.4, -0.4), "join %d", tc.join)
+		assert.False(t, covered(vertices, triangles, 11.5, -1.5), "join %d", tc.join)
+		// the inside of the turn
+		assert.True(t, covered(vertices, triangles, 9.5, 0.5), "join %d", tc.join)
+		assert.False(t, covered(vertices, triangles, 8.5, 1.5), "join %d", tc.join)
+	}
+
+	// a hairpin whose miter would reach about ten widths out is bevelled under the default limit
+	hairpin := []float64{0, 0, 10, 0, 0, 1}
+	maxX := func(vertices []float64) float64 {
+		m := math.Inf(-1)
+		for i := 0; i < len(vertices); i += 2 {
+			m = math.Max(m, vertices[i])
+		}
+		return m
+	}
+	vertices, _ := stroke(t, hairpin, earcut.StrokeOptions{Width: 2})
+	assert.LessOrEqual(t, maxX(vertices), 11.0)
+	vertices, _ = stroke(t, hairpin, earcut.StrokeOptions{Width: 2, MiterLimit: 30})
+	assert.Greater(t, maxX(vertices), 25.0)
+
+	// turning straight back rounds off the end like a cap
+	vertices, triangles := stroke(t, []float64{0, 0, 10, 0, 5, 0}, earcut.StrokeOptions{Width: 2, Join: earcut.RoundJoin})
+	assert.True(t, covered(vertices, triangles, 10.9, 0))
+	assert.False(t, covered(vertices, triangles, 11.1, 0))
+}
+
+func TestStrokeClosed(t *testing.T) {
+	ring := []float64{0, 0, 10, 0, 10, 10, 0, 10, 0, 0}
+	vertices, triangles := stroke(t, ring, earcut.StrokeOptions{Width: 2, Closed: true, Cap: earcut.SquareCap})
+	// every corner is mitred, including the one where the ring closes, and nothing is capped
+	for _, p := range [][2]float64{{-0.95, -0.95}, {10.95, -0.95}, {10.95, 10.95}, {-0.95, 10.95}, {5, 0.9}, {0.9, 5}} {
+		assert.True(t, covered(vertices, triangles, p[0], p[1]), "%v", p)
+	}
+	for _, p := range [][2]float64{{5, 5}, {5, 1.5}, {-1.5, 0}, {0, -1.5}} {
+		assert.False(t, covered(vertices, triangles, p[0], p[1]), "%v", p)
+	}
+}
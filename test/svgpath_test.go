@@ -0,0 +1,151 @@
+package earcut
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"earcut-go/pkg/earcut"
+	"earcut-go/pkg/earcut/svgpath"
+)
+
+func TestParsePath(t *testing.T) {
+	for _, tc := range []struct {
+		d     string
+		rings [][]float64
+	}{
+		{"M0 0 L10 0 L10 10 Z", [][]float64{{0, 0, 10, 0, 10, 10}}},
+		{"m10 10 h10 v10 h-10 z", [][]float64{{10, 10, 20, 10, 20, 20, 10, 20}}},
+		// pairs after a moveto are lines; numbers end where the next one starts
+		{"M0,0 10-10.5.5 0", [][]float64{{0, 0, 10, -10.5, 0.5, 0}}},
+		{"M0 0 1e1 0 10 1E1 0 0", [][]float64{{0, 0, 10, 0, 10, 10}}},
+		// a subpath after a closepath starts where the previous one did; an explicit closing
+		// point and repeated points are dropped
+		{"M0 0 H2 V2 Z L-2 0 L-2 -2 L0 0 L0 0", [][]float64{{0, 0, 2, 0, 2, 2}, {0, 0, -2, 0, -2, -2}}},
+		// subpaths without area are left out
+		{"M0 0 L5 5 M1 1 Z", nil},
+	} {
+		rings, err := svgpath.Parse(tc.d, 0)
+		require.NoError(t, err, tc.d)
+		assert.Equal(t, tc.rings, rings, tc.d)
+	}
+}
+
+func TestParsePathCurves(t *testing.T) {
+	// a quarter circle of radius 10 drawn with the usual cubic
+	rings, err := svgpath.Parse("M10 0 C10 5.5228 5.5228 10 0 10 L0 0 Z", 0.01)
+	require.NoError(t, err)
+	require.Len(t, rings, 1)
+	ring := rings[0]
+	assert.Greater(t, len(ring)/2, 10)
+	for i := 0; i < len(ring)-2; i += 2 {
+		assert.InDelta(t, 10, math.Hypot(ring[i], ring[i+1]), 0.01)
+	}
+
+	// a looser tolerance takes fewer points
+	coarse, err := svgpath.Parse("M10 0 C10 5.5228 5.5228 10 0 10 L0 0 Z", 1)
+	require.NoError(t, err)
+	assert.Less(t, len(coarse[0]), len(ring))
+
+	// smooth curves reflect the previous control point
+	for _, pair := range [][2]string{
+		{"M0 0 C0 10 10 10 10 0 S20 -10 20 0 Z", "M0 0 C0 10 10 10 10 0 C10 -10 20 -10 20 0 Z"},
+		{"M0 0 Q5 10 10 0 T20 0 Z", "M0 0 Q5 10 10 0 Q15 -10 20 0 Z"},
+		{"M0 0 q5 10 10 0 t10 0 z", "M0 0 Q5 10 10 0 Q15 -10 20 0 Z"},
+		// without a previous curve of the same kind, the current point
+		{"M0 0 L0 10 S10 10 10 0 Z", "M0 0 L0 10 C0 10 10 10 10 0 Z"},
+	} {
+		a, err := svgpath.Parse(pair[0], 0)
+		require.NoError(t, err)
+		b, err := svgpath.Parse(pair[1], 0)
+		require.NoError(t, err)
+		assert.InDeltaSlice(t, b[0], a[0], 1e-9, pair[0])
+	}
+}
+
+func TestParsePathArcs(t *testing.T) {
+	const tolerance = 0.05
+	// a circle of radius 10 from two half arcs
+	rings, err := svgpath.Parse("M-10 0 A10 10 0 0 1 10 0 A10 10 0 0 1 -10 0Z", tolerance)
+	require.NoError(t, err)
+	require.Len(t, rings, 1)
+	for i := 0; i < len(rings[0]); i += 2 {
+		assert.InDelta(t, 10, math.Hypot(rings[0][i], rings[0][i+1]), 1e-9)
+	}
+	_, triangles, err := svgpath.Triangulate("M-10 0 A10 10 0 0 1 10 0 A10 10 0 0 1 -10 0Z", tolerance, earcut.NonZero)
+	require.NoError(t, err)
+	// the chords cut off no more than the tolerance along the perimeter
+	area := trianglesArea(rings[0], triangles)
+	assert.LessOrEqual(t, area, 100*math.Pi)
+	assert.Greater(t, area, 100*math.Pi-2*math.Pi*10*tolerance)
+
+	// flags may run into the numbers after them; a radius too small to reach is scaled up, so
+	// this is the lower half circle of radius 5
+	rings, err = svgpath.Parse("M0 0a1 1 0 1010 0z", 0)
+	require.NoError(t, err)
+	require.Len(t, rings, 1)
+	for i := 0; i < len(rings[0]); i += 2 {
+		assert.InDelta(t, 5, math.Hypot(rings[0][i]-5, rings[0][i+1]), 1e-9)
+		assert.GreaterOrEqual(t, rings[0][i+1], -1e-9)
+	}
+
+	// a rotated ellipse keeps its end points and passes within tolerance of the centre form
+	rings, err = svgpath.Parse("M0 0 A20 10 30 1 1 10 10 Z", tolerance)
+	require.NoError(t, err)
+	assert.InDeltaSlice(t, []float64{0, 0}, rings[0][:2], 1e-9)
+	assert.InDeltaSlice(t, []float64{10, 10}, rings[0][len(rings[0])-2:], 1e-9)
+
+	// a tolerance far below the radius still draws the arc, with a bounded number of points
+	rings, err = svgpath.Parse("M0 0 A 1 1 0 0 1 2 0 Z", 1e-17)
+	require.NoError(t, err)
+	require.Len(t, rings, 1)
+	assert.Greater(t, len(rings[0])/2, 1000)
+	assert.LessOrEqual(t, len(rings[0])/2, 1<<16+1)
+	for i := 0; i < len(rings[0]); i += 2 {
+		assert.InDelta(t, 1, math.Hypot(rings[0][i]-1, rings[0][i+1]), 1e-9)
+	}
+}
+
+func TestPathFillRules(t *testing.T) {
+	// a square with a square inside, drawn in the same direction and against it
+	same := "M0 0 H10 V10 H0 Z M3 3 H7 V7 H3 Z"
+	against := "M0 0 H10 V10 H0 Z M3 3 V7 H7 V3 Z"
+	for _, tc := range []struct {
+		d    string
+		rule earcut.FillRule
+		area float64
+	}{
+		{same, earcut.EvenOdd, 84},
+		{same, earcut.NonZero, 100},
+		{against, earcut.EvenOdd, 84},
+		{against, earcut.NonZero, 84},
+	} {
+		vertices, triangles, err := svgpath.Triangulate(tc.d, 0, tc.rule)
+		require.NoError(t, err)
+		assert.InDelta(t, tc.area, trianglesArea(vertices, triangles), 1e-9, tc.d)
+	}
+
+	polygons, err := svgpath.Polygons(same, 0, earcut.EvenOdd)
+	require.NoError(t, err)
+	require.Len(t, polygons, 1)
+	assert.Equal(t, []int{4}, polygons[0].Holes)
+	triangles := earcut.Earcut(polygons[0].Data, polygons[0].Holes, polygons[0].Dim)
+	assert.InDelta(t, 84, trianglesArea(polygons[0].Data, triangles), 1e-9)
+}
+
+func TestParsePathErrors(t *testing.T) {
+	for _, d := range []string{
+		"L0 0 L1 0 L1 1",
+		"M0 0 L1",
+		"M0 0 L1 1 Z 2 2",
+		"M0 0 A1 1 0 2 0 1 1",
+		"M0 0 X1 1",
+		"M0 0 L1 . 1",
+		"10 10",
+	} {
+		_, err := svgpath.Parse(d, 0)
+		assert.ErrorIs(t, err, svgpath.ErrSyntax, d)
+	}
+}
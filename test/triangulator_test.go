@@ -0,0 +1,74 @@
+package earcut
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"earcut-go/pkg/earcut"
+)
+
+// a building-footprint-sized polygon with two holes
+func footprint() ([]float64, []int) {
+	data := circle(100, 0, 0, 100)
+	data = append(data, circle(12, -40, 0, 20)...)
+	data = append(data, circle(12, 40, 0, 20)...)
+	return data, []int{100, 112}
+}
+
+func TestTriangulatorMatchesEarcut(t *testing.T) {
+	data, holes := footprint()
+	var tr earcut.Triangulator
+
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, earcut.Earcut(data, holes, 2), tr.Triangulate(nil, data, holes, 2))
+	}
+
+	small := []float64{10, 0, 0, 50, 60, 60, 70, 10}
+	assert.Equal(t, []int{1, 0, 3, 3, 2, 1}, tr.Triangulate(nil, small, nil, 2))
+}
+
+func TestTriangulatorAppends(t *testing.T) {
+	var tr earcut.Triangulator
+	data := []float64{10, 0, 0, 50, 60, 60, 70, 10}
+
+	dst := tr.Triangulate([]int{7}, data, nil, 2)
+	assert.Equal(t, []int{7, 1, 0, 3, 3, 2, 1}, dst)
+}
+
+func TestTriangulatorOptions(t *testing.T) {
+	data := []float64{3, 3, 0, 1, 5, 5, 5, 4, 1, 3, 4, 0, 2, 2, 4, 5}
+	tr := earcut.Triangulator{Options: earcut.Options{MaxPass: earcut.PassEars}}
+	assert.Equal(t,
+		earcut.EarcutWithOptions(data, nil, 2, earcut.Options{MaxPass: earcut.PassEars}),
+		tr.Triangulate(nil, data, nil, 2))
+}
+
+func TestTriangulatorAllocations(t *testing.T) {
+	data, holes := footprint()
+	var tr earcut.Triangulator
+	dst := tr.Triangulate(nil, data, holes, 2)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		dst = tr.Triangulate(dst[:0], data, holes, 2)
+	})
+	assert.Zero(t, allocs)
+}
+
+func BenchmarkEarcut(b *testing.B) {
+	data, holes := footprint()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		earcut.Earcut(data, holes, 2)
+	}
+}
+
+func BenchmarkTriangulator(b *testing.B) {
+	data, holes := footprint()
+	var tr earcut.Triangulator
+	var dst []int
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		dst = tr.Triangulate(dst[:0], data, holes, 2)
+	}
+}
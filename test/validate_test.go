@@ -0,0 +1,119 @@
+package earcut
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"earcut-go/pkg/earcut"
+)
+
+func TestValidate(t *testing.T) {
+	square := []float64{0, 0, 10, 0, 10, 10, 0, 10}
+	hole := []float64{2, 2, 2, 4, 4, 4, 4, 2}
+
+	tests := []struct {
+		name  string
+		data  []float64
+		holes []int
+		want  []earcut.Problem
+	}{
+		{"valid", append(square, hole...), []int{4}, nil},
+		{"touching hole", append(square, 0, 5, 3, 6, 3, 4), []int{4}, nil},
+		{"duplicate", []float64{0, 0, 10, 0, 10, 0, 10, 10, 0, 10, 0, 0}, nil, []earcut.Problem{
+			{Kind: earcut.DuplicateVertex, Ring: 0, Other: -1, Vertex: 2, X: 10, Y: 0},
+			{Kind: earcut.DuplicateVertex, Ring: 0, Other: -1, Vertex: 5, X: 0, Y: 0},
+		}},
+		{"bowtie", []float64{0, 0, 2, 2, 2, 0, 0, 2}, nil, []earcut.Problem{
+			{Kind: earcut.SelfIntersection, Ring: 0, Other: -1, Vertex: 0, X: 1, Y: 1},
+		}},
+		{"spike", []float64{0, 0, 10, 0, 10, 10, 10, 15, 10, 10, 0, 10}, nil, []earcut.Problem{
+			{Kind: earcut.Spike, Ring: 0, Other: -1, Vertex: 3, X: 10, Y: 15},
+		}},
+		{"clockwise shell", []float64{0, 0, 0, 10, 10, 10, 10, 0}, nil, []earcut.Problem{
+			{Kind: earcut.WrongWinding, Ring: 0, Other: -1, Vertex: -1, X: 0, Y: 0},
+		}},
+		{"zero area", append(square, 1, 1, 2, 2, 3, 3), []int{4}, []earcut.Problem{
+			{Kind: earcut.ZeroArea, Ring: 1, Other: -1, Vertex: -1, X: 1, Y: 1},
+		}},
+		{"empty", nil, nil, []earcut.Problem{
+			{Kind: earcut.ZeroArea, Ring: 0, Other: -1, Vertex: -1},
+		}},
+		{"empty hole", append(square, hole...), []int{4, 8}, []earcut.Problem{
+			{Kind: earcut.ZeroArea, Ring: 2, Other: -1, Vertex: -1},
+		}},
+		{"hole outside", append(square, 12, 2, 12, 4, 14, 4), []int{4}, []earcut.Problem{
+			{Kind: earcut.HoleOutsideShell, Ring: 1, Other: 0, Vertex: 4, X: 12, Y: 2},
+		}},
+		{"hole crossing", append(square, 8, 2, 8, 4, 12, 4, 12, 2), []int{4}, []earcut.Problem{
+			{Kind: earcut.HoleCrossesShell, Ring: 0, Other: 1, Vertex: 1, X: 10, Y: 2},
+			{Kind: earcut.HoleCrossesShell, Ring: 0, Other: 1, Vertex: 1, X: 10, Y: 4},
+		}},
+		{"nested holes", append(append(square, hole...), 2.5, 2.5, 2.5, 3, 3, 3), []int{4, 8}, []earcut.Problem{
+			{Kind: earcut.HolesOverlap, Ring: 2, Other: 1, Vertex: 8, X: 2.5, Y: 2.5},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, earcut.Validate(tt.data, tt.holes, 2))
+		})
+	}
+}
+
+func TestProblemString(t *testing.T) {
+	p := earcut.Problem{Kind: earcut.HoleCrossesShell, Ring: 0, Other: 1, Vertex: 1, X: 10, Y: 2}
+	assert.Equal(t, "hole crosses shell in ring 0 and ring 1 at vertex 1 (10, 2)", p.String())
+}
+
+func TestRepair(t *testing.T) {
+	tests := []struct {
+		name  string
+		data  []float64
+		holes []int
+		// outer ring vertices and hole count of each polygon, and the area of all of them
+		rings [][2]int
+		area  float64
+	}{
+		{"bowtie", []float64{0, 0, 2, 2, 2, 0, 0, 2}, nil, [][2]int{{3, 0}, {3, 0}}, 2},
+		{"spike and duplicates", []float64{0, 0, 10, 0, 10, 10, 10, 15, 10, 10, 0, 10, 0, 10, 0, 0}, nil,
+			[][2]int{{4, 0}}, 100},
+		{"clockwise", []float64{0, 0, 0, 10, 10, 10, 10, 0, 2, 2, 4, 2, 4, 4}, []int{4}, [][2]int{{4, 1}}, 98},
+		{"figure eight with hole", []float64{0, 0, 4, 0, 4, 4, 8, 4, 8, 8, 4, 8, 4, 4, 0, 4, 5, 5, 6, 5, 6, 6}, []int{8},
+			[][2]int{{4, 0}, {4, 1}}, 31.5},
+		{"inverted hole", []float64{5, 0, 10, 0, 10, 10, 0, 10, 0, 0, 5, 0, 4, 3, 6, 3}, nil, [][2]int{{5, 1}}, 97},
+		{"hole outside", []float64{0, 0, 10, 0, 10, 10, 0, 10, 12, 2, 12, 4, 14, 4}, []int{4}, [][2]int{{4, 0}}, 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			polygons := earcut.Repair(tt.data, tt.holes, 2)
+			require.Len(t, polygons, len(tt.rings))
+
+			area := 0.0
+			for i, p := range polygons {
+				outer := len(p.Data) / 2
+				if len(p.Holes) > 0 {
+					outer = p.Holes[0]
+				}
+				assert.Equal(t, tt.rings[i], [2]int{outer, len(p.Holes)})
+				assert.Empty(t, earcut.Validate(p.Data, p.Holes, p.Dim))
+
+				triangles := earcut.Earcut(p.Data, p.Holes, p.Dim)
+				assert.InDelta(t, 0, earcut.Deviation(p.Data, p.Holes, p.Dim, triangles), 1e-12)
+				area += ringsArea(p.Data, p.Holes)
+			}
+			assert.InDelta(t, tt.area, area, 1e-9)
+		})
+	}
+}
+
+func TestRepairInterpolates(t *testing.T) {
+	// a bowtie rising from z=0 to z=4; the crossing is added halfway up one of the edges
+	data := []float64{0, 0, 0, 2, 2, 4, 2, 0, 0, 0, 2, 4}
+	polygons := earcut.Repair(data, nil, 3)
+	require.Len(t, polygons, 2)
+	for _, p := range polygons {
+		assert.Equal(t, 3, p.Dim)
+		assert.Contains(t, [][]float64{p.Data[0:3], p.Data[3:6], p.Data[6:9]}, []float64{1, 1, 2})
+	}
+}
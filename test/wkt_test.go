@@ -0,0 +1,220 @@
+package earcut
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"earcut-go/pkg/earcut"
+	"earcut-go/pkg/earcut/wkt"
+)
+
+func TestWKTPolygonWithHole(t *testing.T) {
+	g, err := wkt.Parse("POLYGON ((0 0, 10 0, 10 10, 0 10, 0 0), (2 2, 2 8, 8 8, 8 2, 2 2))")
+	assert.NoError(t, err)
+	assert.Equal(t, wkt.XY, g.Layout)
+	assert.Equal(t, []earcut.Polygon{{
+		Data:  []float64{0, 0, 10, 0, 10, 10, 0, 10, 2, 2, 2, 8, 8, 8, 8, 2},
+		Holes: []int{4},
+		Dim:   2,
+	}}, g.Polygons)
+
+	data, triangles := g.Triangulate()
+	assert.Equal(t, 8*3, len(triangles))
+	assert.Zero(t, earcut.Deviation(data, []int{4}, 2, triangles))
+}
+
+func TestWKTMultiPolygonLayouts(t *testing.T) {
+	tests := []struct {
+		wkt    string
+		layout wkt.Layout
+		srid   int
+	}{
+		{"SRID=4326;MULTIPOLYGON Z (((0 0 1, 1 0 1, 1 1 1, 0 0 1)), ((5 5 2, 6 5 2, 6 6 2, 5 5 2)))", wkt.XYZ, 4326},
+		{"multipolygon m (((0 0 1, 1 0 1, 1 1 1, 0 0 1)), ((5 5 2, 6 5 2, 6 6 2, 5 5 2)))", wkt.XYM, 0},
+		{"MULTIPOLYGON (((0 0 1, 1 0 1, 1 1 1, 0 0 1)), EMPTY, ((5 5 2, 6 5 2, 6 6 2, 5 5 2)))", wkt.XYZ, 0},
+	}
+	for _, tt := range tests {
+		g, err := wkt.Parse(tt.wkt)
+		assert.NoError(t, err, tt.wkt)
+		assert.Equal(t, tt.layout, g.Layout)
+		assert.Equal(t, tt.srid, g.SRID)
+		assert.Equal(t, 2, len(g.Polygons))
+
+		data, triangles := g.Triangulate()
+		assert.Equal(t, 18, len(data))
+		assert.Equal(t, []int{0, 1, 2, 3, 4, 5}, sortedCopy(triangles))
+	}
+
+	g, err := wkt.Parse("POLYGON ZM ((0 0 1 2, 1 0 1 2, 1 1 1 2, 0 0 1 2))")
+	assert.NoError(t, err)
+	assert.Equal(t, 4, g.Polygons[0].Dim)
+
+	g, err = wkt.Parse("POLYGON EMPTY")
+	assert.NoError(t, err)
+	assert.Empty(t, g.Polygons)
+}
+
+func TestWKTErrors(t *testing.T) {
+	tests := []struct {
+		wkt string
+		err error
+	}{
+		{"POINT (1 2)", wkt.ErrUnsupportedType},
+		{"", wkt.ErrSyntax},
+		{"POLYGON ((0 0, 1 0, 1 1)", wkt.ErrSyntax},
+		{"POLYGON ((0 0, 1 x, 1 1))", wkt.ErrSyntax},
+		{"POLYGON Z ((0 0, 1 0, 1 1))", wkt.ErrSyntax},
+		{"POLYGON ((0 0, 1 0 0, 1 1))", wkt.ErrSyntax},
+		{"POLYGON ((0 0, 1 0, 1 1)) junk", wkt.ErrSyntax},
+		{"SRID=abc;POLYGON ((0 0, 1 0, 1 1))", wkt.ErrSyntax},
+		{"GEOMETRYCOLLECTION Z (TRIANGLE ((0 0 0, 1 0 0, 1 1 0, 0 0 0)), TRIANGLE M ((0 0 0, 1 0 0, 1 1 0, 0 0 0)))", wkt.ErrSyntax},
+		{"GEOMETRYCOLLECTION (POINT (1 2))", wkt.ErrUnsupportedType},
+	}
+	for _, tt := range tests {
+		_, err := wkt.Parse(tt.wkt)
+		assert.ErrorIs(t, err, tt.err, tt.wkt)
+	}
+}
+
+// wkbPolygon encodes a single-ring 2D polygon, writing an EWKB SRID when srid is non-zero
+func wkbPolygon(order binary.AppendByteOrder, typ uint32, srid uint32, rings ...[]float64) []byte {
+	b := []byte{1}
+	if order == binary.BigEndian {
+		b[0] = 0
+	}
+	b = order.AppendUint32(b, typ)
+	if srid != 0 {
+		b = order.AppendUint32(b, srid)
+	}
+	b = order.AppendUint32(b, uint32(len(rings)))
+	for _, ring := range rings {
+		b = order.AppendUint32(b, uint32(len(ring)/2))
+		for _, v := range ring {
+			b = order.AppendUint64(b, math.Float64bits(v))
+		}
+	}
+	return b
+}
+
+func TestWKTEmptyExterior(t *testing.T) {
+	// an empty exterior empties the polygon instead of promoting its hole to the shell
+	square := []float64{0, 0, 10, 0, 10, 10, 0, 10, 0, 0}
+	hole := []float64{2, 2, 2, 8, 8, 8, 8, 2, 2, 2}
+	g, err := wkt.Parse("MULTIPOLYGON ((EMPTY, (2 2, 2 8, 8 8, 8 2, 2 2)), ((0 0, 10 0, 10 10, 0 10, 0 0), EMPTY))")
+	assert.NoError(t, err)
+	assert.Equal(t, []earcut.Polygon{{Data: square[:8], Dim: 2}}, g.Polygons)
+
+	g, err = wkt.ParseWKB(wkbPolygon(binary.LittleEndian, 3, 0, nil, hole))
+	assert.NoError(t, err)
+	assert.Empty(t, g.Polygons)
+
+	g, err = wkt.ParseWKB(wkbPolygon(binary.LittleEndian, 3, 0, square, nil, hole))
+	assert.NoError(t, err)
+	assert.Equal(t, []earcut.Polygon{{Data: append(square[:8:8], hole[:8]...), Holes: []int{4}, Dim: 2}}, g.Polygons)
+}
+
+func sortedCopy(a []int) []int {
+	b := slices.Clone(a)
+	slices.Sort(b)
+	return b
+}
+
+func TestWKBPolygon(t *testing.T) {
+	ring := []float64{0, 0, 10, 0, 10, 10, 0, 10, 0, 0}
+
+	g, err := wkt.ParseWKB(wkbPolygon(binary.LittleEndian, 3, 0, ring))
+	assert.NoError(t, err)
+	assert.Equal(t, wkt.XY, g.Layout)
+	assert.Equal(t, ring[:8], g.Polygons[0].Data)
+
+	g, err = wkt.ParseWKB(wkbPolygon(binary.BigEndian, 3, 0, ring))
+	assert.NoError(t, err)
+	assert.Equal(t, ring[:8], g.Polygons[0].Data)
+
+	g, err = wkt.ParseHex(hex.EncodeToString(wkbPolygon(binary.LittleEndian, 3|0x20000000, 3857, ring)))
+	assert.NoError(t, err)
+	assert.Equal(t, 3857, g.SRID)
+	assert.Equal(t, ring[:8], g.Polygons[0].Data)
+
+	_, err = wkt.ParseWKB(wkbPolygon(binary.LittleEndian, 3, 0, ring)[:30])
+	assert.ErrorIs(t, err, wkt.ErrTruncated)
+
+	_, err = wkt.ParseWKB(wkbPolygon(binary.LittleEndian, 1, 0, ring))
+	assert.ErrorIs(t, err, wkt.ErrUnsupportedType)
+}
+
+func TestWKBMultiPolygonZ(t *testing.T) {
+	// ISO POLYGON Z members inside an EWKB MULTIPOLYGON with the Z flag
+	ring := []float64{0, 0, 5, 4, 0, 5, 4, 4, 5, 0, 0, 5}
+	member := []byte{1}
+	member = binary.LittleEndian.AppendUint32(member, 1003)
+	member = binary.LittleEndian.AppendUint32(member, 1)
+	member = binary.LittleEndian.AppendUint32(member, 4)
+	for _, v := range ring {
+		member = binary.LittleEndian.AppendUint64(member, math.Float64bits(v))
+	}
+
+	b := []byte{1}
+	b = binary.LittleEndian.AppendUint32(b, 6|0x80000000)
+	b = binary.LittleEndian.AppendUint32(b, 2)
+	b = append(append(b, member...), member...)
+
+	g, err := wkt.ParseWKB(b)
+	assert.NoError(t, err)
+	assert.Equal(t, wkt.XYZ, g.Layout)
+	assert.Equal(t, 2, len(g.Polygons))
+	assert.Equal(t, ring[:9], g.Polygons[1].Data)
+}
+
+func TestMarshalTriangles(t *testing.T) {
+	data := []float64{0, 0, 1, 0, 1, 1, 0, 1}
+	triangles := []int{0, 1, 2, 0, 2, 3}
+
+	assert.Equal(t,
+		"TIN (((0 0, 1 0, 1 1, 0 0)), ((0 0, 1 1, 0 1, 0 0)))",
+		wkt.MarshalWKT(wkt.TIN, wkt.XY, data, triangles))
+	assert.Equal(t,
+		"GEOMETRYCOLLECTION (TRIANGLE ((0 0, 1 0, 1 1, 0 0)), TRIANGLE ((0 0, 1 1, 0 1, 0 0)))",
+		wkt.MarshalWKT(wkt.GeometryCollection, wkt.XY, data, triangles))
+	assert.Equal(t, "TIN Z EMPTY", wkt.MarshalWKT(wkt.TIN, wkt.XYZ, nil, nil))
+
+	b := wkt.MarshalWKB(wkt.TIN, wkt.XYZ, []float64{0, 0, 0, 1, 0, 0, 1, 1, 0}, []int{0, 1, 2}, binary.LittleEndian)
+	assert.Equal(t, 9+13+4*3*8, len(b))
+	assert.Equal(t, uint32(1016), binary.LittleEndian.Uint32(b[1:]))
+	assert.Equal(t, uint32(1), binary.LittleEndian.Uint32(b[5:]))
+	assert.Equal(t, uint32(1017), binary.LittleEndian.Uint32(b[10:]))
+
+	b = wkt.MarshalWKB(wkt.GeometryCollection, wkt.XY, data, triangles, binary.BigEndian)
+	assert.Equal(t, byte(0), b[0])
+	assert.Equal(t, uint32(7), binary.BigEndian.Uint32(b[1:]))
+	assert.Equal(t, uint32(2), binary.BigEndian.Uint32(b[5:]))
+}
+
+func TestMarshalRoundTrip(t *testing.T) {
+	data := []float64{0, 0, 1, 1, 0, 2, 1, 1, 3, 0, 1, 4}
+	triangles := []int{0, 1, 2, 0, 2, 3}
+	want := []earcut.Polygon{
+		{Data: []float64{0, 0, 1, 1, 0, 2, 1, 1, 3}, Dim: 3},
+		{Data: []float64{0, 0, 1, 1, 1, 3, 0, 1, 4}, Dim: 3},
+	}
+
+	s := wkt.MarshalWKT(wkt.GeometryCollection, wkt.XYZ, data, triangles)
+	assert.Equal(t, "GEOMETRYCOLLECTION Z (TRIANGLE Z ((0 0 1, 1 0 2, 1 1 3, 0 0 1)), TRIANGLE Z ((0 0 1, 1 1 3, 0 1 4, 0 0 1)))", s)
+	for _, form := range []wkt.Form{wkt.TIN, wkt.GeometryCollection} {
+		g, err := wkt.Parse(wkt.MarshalWKT(form, wkt.XYZ, data, triangles))
+		assert.NoError(t, err)
+		assert.Equal(t, wkt.XYZ, g.Layout)
+		assert.Equal(t, want, g.Polygons)
+
+		g, err = wkt.ParseWKB(wkt.MarshalWKB(form, wkt.XYZ, data, triangles, binary.LittleEndian))
+		assert.NoError(t, err)
+		assert.Equal(t, wkt.XYZ, g.Layout)
+		assert.Equal(t, want, g.Polygons)
+	}
+	assert.Equal(t, "GEOMETRYCOLLECTION ZM EMPTY", wkt.MarshalWKT(wkt.GeometryCollection, wkt.XYZM, nil, nil))
+}